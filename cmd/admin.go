@@ -0,0 +1,35 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/furkanpala/post-app/internal/database"
+)
+
+const adminUsage = "usage: main admin grant|revoke <username>"
+
+// runAdmin function handles the admin subcommand.
+// "grant" gives admin rights to the user, "revoke" takes them back.
+func runAdmin(store database.Store, args []string) error {
+	if len(args) != 2 {
+		return errors.New(adminUsage)
+	}
+
+	var admin bool
+	switch args[0] {
+	case "grant":
+		admin = true
+	case "revoke":
+		admin = false
+	default:
+		return errors.New(adminUsage)
+	}
+
+	err := store.SetAdmin(args[1], admin)
+	if err == database.ErrNotFound {
+		return fmt.Errorf("user %s not found", args[1])
+	}
+
+	return err
+}
@@ -6,11 +6,15 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/furkanpala/post-app/internal/database"
+	"github.com/furkanpala/post-app/internal/env"
 	httphandlers "github.com/furkanpala/post-app/internal/http/handlers"
 	"github.com/furkanpala/post-app/internal/http/middleware"
+	"github.com/furkanpala/post-app/internal/storage"
 
 	"github.com/gorilla/mux"
 )
@@ -34,7 +38,9 @@ func (h spaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.ServeFile(w, r, filepath.Join(h.staticPath, h.indexPath))
 		return
 	} else if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		// Error of the file system is logged but not shown to the client
+		log.Printf("Request %s: %v\n", r.Header.Get(httphandlers.RequestIDHeader), err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
 
@@ -42,34 +48,115 @@ func (h spaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	http.FileServer(http.Dir(h.staticPath)).ServeHTTP(w, r)
 }
 
+// durationFromEnv parses value of an environment variable as a duration.
+// Returns def if value is empty or invalid.
+func durationFromEnv(value string, def time.Duration) time.Duration {
+	if value == "" {
+		return def
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("Invalid duration %q, using %v\n", value, def)
+		return def
+	}
+
+	return d
+}
+
+// withTimeout times out the requests served by h after timeout,
+// except the requests at the untimed paths: the long-lived streams and the file downloads,
+// whose responses would otherwise be buffered in memory by http.TimeoutHandler.
+// Like the patterns of http.ServeMux, paths ending with a slash match every path under them.
+func withTimeout(h http.Handler, timeout time.Duration, untimed ...string) http.Handler {
+	timed := http.TimeoutHandler(h, timeout, "Request timed out")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, path := range untimed {
+			if r.URL.Path == path || (strings.HasSuffix(path, "/") && strings.HasPrefix(r.URL.Path, path)) {
+				h.ServeHTTP(w, r)
+				return
+			}
+		}
+		timed.ServeHTTP(w, r)
+	})
+}
+
 func main() {
 	var port string
 	if port = os.Getenv("PORT"); port == "" {
 		port = "3000"
 	}
 
-	err := database.OpenDatabase()
+	dsn := env.DatabaseURL
+	if dsn == "" {
+		dsn = "./post-app.db"
+	}
+
+	store, err := database.Open(dsn)
 	if err != nil {
-		log.Fatal("Database error")
+		log.Fatal("Database error: ", err)
 	}
 
-	defer database.CloseDatabase()
+	defer store.Close()
 
-	err = database.CreateUsersTable()
-	if err != nil {
-		log.Fatal("Database error")
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(store, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
 
-	err = database.CreatePostsTable()
+	err = store.MigrateUp()
 	if err != nil {
-		log.Fatal("Database error")
+		log.Fatal("Database error: ", err)
 	}
 
-	err = database.CreateBlacklistTable()
+	if len(os.Args) > 1 && os.Args[1] == "admin" {
+		if err := runAdmin(store, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	cachedStore := database.NewBlacklistCache(store, durationFromEnv(env.BlacklistCacheTTL, time.Minute))
+
+	attachmentsDir := env.AttachmentsDir
+	if attachmentsDir == "" {
+		attachmentsDir = "./attachments"
+	}
+
+	blobs, err := storage.NewLocalBlobStore(attachmentsDir)
 	if err != nil {
-		log.Fatal("Database error")
+		log.Fatal("Attachment storage error: ", err)
 	}
 
+	handler := httphandlers.NewHandler(cachedStore, blobs)
+	if env.Reactions != "" {
+		handler.SetReactionTypes(strings.Split(env.Reactions, ","))
+	}
+
+	// Janitors and the scheduler run until stopJanitor is closed, background waits for them to return
+	stopJanitor := make(chan struct{})
+	var background sync.WaitGroup
+	runInBackground := func(run func()) {
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			run()
+		}()
+	}
+	runInBackground(func() {
+		database.RunBlacklistJanitor(cachedStore, durationFromEnv(env.BlacklistPurgeInterval, time.Hour), stopJanitor)
+	})
+	runInBackground(func() {
+		database.RunTrashJanitor(cachedStore, blobs, durationFromEnv(env.TrashRetention, 30*24*time.Hour),
+			durationFromEnv(env.UploadRetention, 24*time.Hour), time.Hour, stopJanitor)
+	})
+	runInBackground(func() {
+		database.RunPublishScheduler(cachedStore, durationFromEnv(env.PublishInterval, time.Minute), handler.PublishScheduled, stopJanitor)
+	})
+
 	router := mux.NewRouter()
 
 	// headers := handlers.AllowedHeaders([]string{"X-Requested-With", "Content-Type", "Authorization"})
@@ -78,27 +165,69 @@ func main() {
 	// credentials := handlers.AllowCredentials()
 
 	// Auth
-	router.Handle("/login", httphandlers.RouteHandler(httphandlers.HandleLogin)).Methods("POST")
-	router.Handle("/register", httphandlers.RouteHandler(httphandlers.HandleRegister)).Methods("POST")
-	router.Handle("/token", httphandlers.RouteHandler(httphandlers.RefreshToken)).Methods("POST")
-	router.Handle("/token/logout", httphandlers.RouteHandler(httphandlers.HandleLogout)).Methods("POST")
+	router.Handle("/login", httphandlers.RouteHandler(handler.HandleLogin)).Methods("POST")
+	router.Handle("/register", httphandlers.RouteHandler(handler.HandleRegister)).Methods("POST")
+	router.Handle("/token", httphandlers.RouteHandler(handler.RefreshToken)).Methods("POST")
+	router.Handle("/token/logout", httphandlers.RouteHandler(handler.HandleLogout)).Methods("POST")
 
 	// Post API
-	router.Handle("/posts", httphandlers.RouteHandler(httphandlers.GetPosts)).Methods("GET")
-	router.Handle("/posts/amount", httphandlers.RouteHandler(httphandlers.GetPostsAmount)).Methods("GET")
-	router.Handle("/posts/{page}", httphandlers.RouteHandler(httphandlers.GetPostsOnPage)).Methods("GET")
-	router.Handle("/posts", middleware.AuthMiddleware(httphandlers.RouteHandler(httphandlers.AddPost)))
+	router.Handle("/posts", middleware.OptionalAuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.GetPosts))).Methods("GET")
+	router.Handle("/posts/amount", httphandlers.RouteHandler(handler.GetPostsAmount)).Methods("GET")
+	router.Handle("/posts/stream", httphandlers.RouteHandler(handler.StreamPosts)).Methods("GET")
+	router.Handle("/posts/search", httphandlers.RouteHandler(handler.SearchPosts)).Methods("GET")
+	// Pages are listed by /posts?page={page} and /posts/page/{page}.
+	// /posts/{page}?as=page lists them for the clients which used /posts/{page} before it addressed posts.
+	router.Handle("/posts/page/{page}", middleware.OptionalAuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.GetPostsOnPage))).Methods("GET")
+	router.Handle("/posts/{page:[0-9]+}", middleware.OptionalAuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.GetLegacyPage))).Queries("as", "page").Methods("GET")
+	router.Handle("/posts/{id:[0-9]+}", middleware.OptionalAuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.GetPost))).Methods("GET")
+	router.Handle("/posts", middleware.AuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.AddPost)))
+	router.Handle("/posts/{id:[0-9]+}", middleware.AuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.EditPost))).Methods("PATCH")
+	router.Handle("/posts/{id:[0-9]+}", middleware.AuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.DeletePost))).Methods("DELETE")
+	router.Handle("/posts/{id:[0-9]+}/restore", middleware.AuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.RestorePost))).Methods("POST")
+	router.Handle("/posts/{id:[0-9]+}/revisions", middleware.OptionalAuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.GetRevisions))).Methods("GET")
+	router.Handle("/posts/{id:[0-9]+}/revisions/{n}", middleware.OptionalAuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.GetRevision))).Methods("GET")
+	router.Handle("/posts/{id:[0-9]+}/revisions/{n}/diff", middleware.OptionalAuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.GetRevisionDiff))).Methods("GET")
+	router.Handle("/posts/{id:[0-9]+}/comments", middleware.OptionalAuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.GetComments))).Methods("GET")
+	router.Handle("/posts/{id:[0-9]+}/comments", middleware.AuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.AddComment))).Methods("POST")
+	router.Handle("/posts/{id:[0-9]+}/comments/{commentID:[0-9]+}", middleware.AuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.EditComment))).Methods("PATCH")
+	router.Handle("/posts/{id:[0-9]+}/comments/{commentID:[0-9]+}", middleware.AuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.DeleteComment))).Methods("DELETE")
+	router.Handle("/posts/{id:[0-9]+}/reactions/{type}", middleware.AuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.PutReaction))).Methods("PUT")
+	router.Handle("/posts/{id:[0-9]+}/reactions/{type}", middleware.AuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.DeleteReaction))).Methods("DELETE")
+	router.Handle("/attachments", middleware.AuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.UploadAttachment))).Methods("POST")
+	router.Handle("/attachments/{id}", httphandlers.RouteHandler(handler.GetAttachment)).Methods("GET", "HEAD")
+	router.Handle("/users/{username}", httphandlers.RouteHandler(handler.GetProfile)).Methods("GET")
+	router.Handle("/users/{username}/posts", middleware.OptionalAuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.GetUserPosts))).Methods("GET")
+	router.Handle("/users/{username}/follow", middleware.AuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.FollowUser))).Methods("PUT")
+	router.Handle("/users/{username}/follow", middleware.AuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.UnfollowUser))).Methods("DELETE")
+	router.Handle("/users/{username}/followers", httphandlers.RouteHandler(handler.GetFollowers)).Methods("GET")
+	router.Handle("/users/{username}/following", httphandlers.RouteHandler(handler.GetFollowing)).Methods("GET")
+	router.Handle("/timeline", middleware.AuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.GetTimeline))).Methods("GET")
+	router.Handle("/tags", httphandlers.RouteHandler(handler.GetTags)).Methods("GET")
+	router.Handle("/me/drafts", middleware.AuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.GetDrafts))).Methods("GET")
+	router.Handle("/me/trash", middleware.AuthMiddleware(cachedStore, httphandlers.RouteHandler(handler.GetTrash))).Methods("GET")
+
+	// Feeds
+	router.Handle("/feed.rss", httphandlers.RouteHandler(handler.GetRSSFeed)).Methods("GET")
+	router.Handle("/feed.atom", httphandlers.RouteHandler(handler.GetAtomFeed)).Methods("GET")
+	router.Handle("/users/{username}/feed.atom", httphandlers.RouteHandler(handler.GetUserAtomFeed)).Methods("GET")
 
 	spa := spaHandler{staticPath: "dist", indexPath: "index.html"}
 	router.PathPrefix("/").Handler(spa)
 
+	// WriteTimeout of the server would also end the streams and slow downloads,
+	// so the other requests are timed out by the handler instead
 	srv := &http.Server{
-		Handler:      router,
-		Addr:         ":" + port,
-		WriteTimeout: 15 * time.Second,
-		ReadTimeout:  15 * time.Second,
+		Handler:     middleware.RequestID(withTimeout(router, 15*time.Second, "/posts/stream", "/attachments/")),
+		Addr:        ":" + port,
+		ReadTimeout: 15 * time.Second,
 	}
 	fmt.Println("Server is running on port " + port)
-	log.Fatal(srv.ListenAndServe())
-
+	err = srv.ListenAndServe()
+
+	// log.Fatal exits without running the deferred calls,
+	// so the background jobs are stopped before and the store is closed after they return
+	close(stopJanitor)
+	background.Wait()
+	store.Close()
+	log.Fatal(err)
 }
@@ -4,9 +4,12 @@ go 1.14
 
 require (
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
-	github.com/gorilla/context v1.1.1
 	github.com/gorilla/mux v1.7.4
-	github.com/mattn/go-sqlite3 v2.0.3+incompatible
+	github.com/lib/pq v1.10.9
+	github.com/mattn/go-sqlite3 v1.14.15
+	github.com/microcosm-cc/bluemonday v1.0.16
 	github.com/satori/go.uuid v1.2.0
+	github.com/yuin/goldmark v1.4.11
 	golang.org/x/crypto v0.0.0-20200403201458-baeed622b8d8
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 )
@@ -0,0 +1,17 @@
+package core
+
+// Attachment is an image uploaded by a user to be shown in a post.
+// PostID is the post it is attached to, 0 until it is attached.
+type Attachment struct {
+	ID          string `json:"id"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	User        string `json:"user"`
+	Date        int64  `json:"date"`
+	PostID      int    `json:"post_id,omitempty"`
+}
+
+// MaxAttachments is the maximum number of attachments of a post
+const MaxAttachments = 10
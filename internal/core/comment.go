@@ -0,0 +1,50 @@
+package core
+
+// Comment struct is a container to store a comment on a post.
+// ParentID is the id of the comment replied to, 0 for the comments on the post itself.
+// Content of a deleted comment is empty, it is kept to hold the thread together.
+type Comment struct {
+	ID        int       `json:"id,omitempty"`
+	PostID    int       `json:"post_id"`
+	ParentID  int       `json:"parent_id,omitempty"`
+	Content   string    `json:"content"`
+	User      string    `json:"user,omitempty"`
+	Date      int64     `json:"date,omitempty"`
+	EditedAt  int64     `json:"edited_at,omitempty"`
+	DeletedAt int64     `json:"deleted_at,omitempty"`
+	Replies   []Comment `json:"replies,omitempty"`
+}
+
+// Threads arranges comments into trees by their ParentID.
+// Comments whose parent is not among comments are the roots.
+// Order of comments is kept at every level.
+func Threads(comments []Comment) []Comment {
+	children := make(map[int][]Comment)
+	ids := make(map[int]bool, len(comments))
+	for _, comment := range comments {
+		ids[comment.ID] = true
+	}
+
+	var roots []Comment
+	for _, comment := range comments {
+		if comment.ParentID != 0 && ids[comment.ParentID] {
+			children[comment.ParentID] = append(children[comment.ParentID], comment)
+		} else {
+			roots = append(roots, comment)
+		}
+	}
+
+	var attach func(comment Comment) Comment
+	attach = func(comment Comment) Comment {
+		for _, child := range children[comment.ID] {
+			comment.Replies = append(comment.Replies, attach(child))
+		}
+		return comment
+	}
+
+	for i := range roots {
+		roots[i] = attach(roots[i])
+	}
+
+	return roots
+}
@@ -1,10 +1,64 @@
 package core
 
 // Post struct is a container to store post's ID,title, content, user and date added.
+// Content is Markdown, ContentHTML the sanitized HTML rendered from it.
+// EditedAt is the time of the last edit, 0 if post is never edited.
+// Version starts from 1 and is incremented on every edit.
+// DeletedAt is the time post is moved into the trash, 0 if it is not deleted.
+// Status is one of PostDraft, PostScheduled and PostPublished, only published posts are listed.
+// PublishAt is the time a scheduled post is published at.
+// Date of an unpublished post is the time it is created, it becomes the publishing time once it is published.
+// CommentCount is the number of comments on the post which are not deleted.
+// Tags are the normalized topics of the post, see NormalizeTags.
+// AttachmentIDs are the ids of the attachments shown in the post, in upload order.
+// Reactions holds the number of each type of reaction given to the post,
+// Reacted the types of reaction the requesting user gave.
 type Post struct {
-	ID      int    `json:"id,omitempty"`
+	ID           int      `json:"id,omitempty"`
+	Title        string   `json:"title"`
+	Content      string   `json:"content"`
+	ContentHTML  string   `json:"content_html"`
+	User         string   `json:"user,omitempty"`
+	Date         int64    `json:"date,omitempty"`
+	EditedAt     int64    `json:"edited_at,omitempty"`
+	Version      int      `json:"version,omitempty"`
+	DeletedAt    int64    `json:"deleted_at,omitempty"`
+	Status       string   `json:"status,omitempty"`
+	PublishAt    int64    `json:"publish_at,omitempty"`
+	CommentCount int      `json:"comment_count"`
+	Tags         []string `json:"tags,omitempty"`
+
+	AttachmentIDs []string `json:"attachment_ids,omitempty"`
+
+	Reactions map[string]int `json:"reactions,omitempty"`
+	Reacted   []string       `json:"reacted,omitempty"`
+}
+
+// Statuses of a post
+const (
+	PostDraft     = "draft"
+	PostScheduled = "scheduled"
+	PostPublished = "published"
+)
+
+// DefaultReactionTypes are the types of reaction users can give to posts unless configured otherwise
+var DefaultReactionTypes = []string{"like", "laugh", "love", "wow", "sad"}
+
+// Revision is a version of a post.
+// Number is the version of the post the revision belongs to,
+// Date is the time the version is created.
+type Revision struct {
+	PostID  int    `json:"post_id"`
+	Number  int    `json:"number"`
 	Title   string `json:"title"`
 	Content string `json:"content"`
-	User    string `json:"user,omitempty"`
-	Date    int64  `json:"date,omitempty"`
+	Date    int64  `json:"date"`
+}
+
+// SearchResult is a post matching a full-text search.
+// Highlights are HTML escaped with the matched terms wrapped in <mark> tags.
+type SearchResult struct {
+	Post
+	TitleHighlight string `json:"title_highlight"`
+	Snippet        string `json:"snippet"`
 }
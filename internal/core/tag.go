@@ -0,0 +1,100 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+const (
+	// MaxTags is the maximum number of tags of a post
+	MaxTags = 10
+	// MaxTagLength is the maximum number of characters of a tag
+	MaxTagLength = 32
+	// MaxTagFilters is the maximum number of tags a listing can be filtered by.
+	// Listed posts have all of the tags, so more than MaxTags filters could never match.
+	MaxTagFilters = MaxTags
+)
+
+var (
+	// ErrTooManyTags is returned when a post has more than MaxTags tags
+	ErrTooManyTags = errors.New("a post can have at most 10 tags")
+	// ErrTooManyTagFilters is returned when a listing is filtered by more than MaxTagFilters tags
+	ErrTooManyTagFilters = errors.New("posts can be filtered by at most 10 tags")
+	// ErrInvalidTag is returned when a tag is empty, too long or has characters other than
+	// letters, digits and "-", "_", ".", "+"
+	ErrInvalidTag = errors.New("tags must be 1 to 32 letters, digits or one of - _ . +")
+)
+
+// Tag is a topic posts are grouped by.
+// Count is the number of posts tagged with it.
+type Tag struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// NormalizeTags trims and lower cases tags, drops a leading "#" and removes duplicates
+// keeping the order tags are given in. Returns an error if any tag is invalid
+// or if there are more than MaxTags of them.
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(normalized) > MaxTags {
+		return nil, ErrTooManyTags
+	}
+
+	return normalized, nil
+}
+
+// NormalizeTagFilters normalizes the tags a listing is filtered by like NormalizeTags.
+// Returns an error if any tag is invalid or if there are more than MaxTagFilters of them.
+func NormalizeTagFilters(tags []string) ([]string, error) {
+	normalized, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(normalized) > MaxTagFilters {
+		return nil, ErrTooManyTagFilters
+	}
+
+	return normalized, nil
+}
+
+// normalizeTags normalizes tags, returning an error if any tag is invalid
+func normalizeTags(tags []string) ([]string, error) {
+	var normalized []string
+	seen := make(map[string]bool, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		if !validTag(tag) {
+			return nil, ErrInvalidTag
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	return normalized, nil
+}
+
+// validTag reports whether the normalized tag is valid
+func validTag(tag string) bool {
+	if tag == "" || len([]rune(tag)) > MaxTagLength {
+		return false
+	}
+
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_.+", r) {
+			return false
+		}
+	}
+
+	return true
+}
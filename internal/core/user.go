@@ -2,10 +2,24 @@ package core
 
 import "golang.org/x/crypto/bcrypt"
 
-// User struct is a container to store user's username and password
+// User struct is a container to store user's username, password and rights
 type User struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
+	// Admin users can edit the posts of others.
+	// It is never read from or written to JSON.
+	Admin bool `json:"-"`
+	// JoinedAt is the time user registered, 0 if it is not known
+	JoinedAt int64 `json:"-"`
+}
+
+// Profile is the public information of a user
+type Profile struct {
+	Username       string `json:"username"`
+	JoinedAt       int64  `json:"joined_at,omitempty"`
+	PostCount      int    `json:"post_count"`
+	FollowerCount  int    `json:"follower_count"`
+	FollowingCount int    `json:"following_count"`
 }
 
 // HashPassword function hashes the user's password with 10 salt
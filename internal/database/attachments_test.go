@@ -0,0 +1,51 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/furkanpala/post-app/internal/core"
+)
+
+func TestPurgeAttachments(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		for _, a := range []core.Attachment{
+			{ID: "attached", ContentType: "image/png", User: "alice", Date: 100},
+			{ID: "old", ContentType: "image/png", User: "alice", Date: 100},
+			{ID: "new", ContentType: "image/png", User: "alice", Date: 300},
+		} {
+			a := a
+			if err := store.AddAttachment(&a); err != nil {
+				t.Fatal(err)
+			}
+		}
+		post := addPost(t, store, core.Post{Title: "Post", Content: "Content", User: "alice", AttachmentIDs: []string{"attached"}})
+
+		ids, err := store.PurgeUnattachedAttachments(200)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(ids, []string{"old"}) {
+			t.Fatalf("purged unattached attachments %v, want the old one", ids)
+		}
+		for _, id := range []string{"attached", "new"} {
+			if _, err := store.FindAttachment(id); err != nil {
+				t.Fatalf("FindAttachment(%q) returned %v after the purge", id, err)
+			}
+		}
+
+		if err := store.DeletePost(post.ID); err != nil {
+			t.Fatal(err)
+		}
+		_, ids, err = store.PurgeDeletedPosts(1 << 40)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(ids, []string{"attached"}) {
+			t.Fatalf("purging the post removed attachments %v, want its attachment", ids)
+		}
+		if _, err := store.FindAttachment("attached"); err != ErrNotFound {
+			t.Fatalf("FindAttachment of a purged attachment returned %v, want ErrNotFound", err)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+var _ Store = (*BlacklistCache)(nil)
+
+// BlacklistCache is a Store which caches the blacklist lookups of the Store it wraps.
+// Revoked jtis are cached until their token expires,
+// jtis which are not revoked are cached for ttl so that
+// revocations made by other servers sharing the database are noticed.
+type BlacklistCache struct {
+	Store
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]blacklistEntry
+}
+
+type blacklistEntry struct {
+	revoked bool
+	until   time.Time
+}
+
+// NewBlacklistCache returns a BlacklistCache in front of store
+func NewBlacklistCache(store Store, ttl time.Duration) *BlacklistCache {
+	return &BlacklistCache{
+		Store:   store,
+		ttl:     ttl,
+		entries: make(map[string]blacklistEntry),
+	}
+}
+
+// BlacklistToken adds the jti into the blacklist of the store and the cache
+func (c *BlacklistCache) BlacklistToken(jti string, expiresAt int64) error {
+	if err := c.Store.BlacklistToken(jti, expiresAt); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.entries[jti] = blacklistEntry{revoked: true, until: time.Unix(expiresAt, 0)}
+	c.mu.Unlock()
+
+	return nil
+}
+
+// FindJTI reports whether the jti is in the blacklist, asking the store only on a cache miss
+func (c *BlacklistCache) FindJTI(jti string) (bool, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[jti]
+	c.mu.Unlock()
+
+	if ok && now.Before(entry.until) {
+		return entry.revoked, nil
+	}
+
+	revoked, err := c.Store.FindJTI(jti)
+	if err != nil {
+		return false, err
+	}
+
+	// Expired tokens are rejected before the blacklist is checked,
+	// so revoked entries need not outlive the token.
+	entry = blacklistEntry{revoked: revoked, until: now.Add(c.ttl)}
+
+	c.mu.Lock()
+	c.entries[jti] = entry
+	c.mu.Unlock()
+
+	return revoked, nil
+}
+
+// PurgeExpiredTokens deletes the expired jtis from the store and drops the stale cache entries
+func (c *BlacklistCache) PurgeExpiredTokens(now int64) (int64, error) {
+	t := time.Unix(now, 0)
+
+	c.mu.Lock()
+	for jti, entry := range c.entries {
+		if !t.Before(entry.until) {
+			delete(c.entries, jti)
+		}
+	}
+	c.mu.Unlock()
+
+	return c.Store.PurgeExpiredTokens(now)
+}
+
+// RunBlacklistJanitor deletes the expired jtis from the blacklist of store every interval.
+// It blocks until stop is closed, so it is meant to be run on its own goroutine.
+func RunBlacklistJanitor(store Store, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			if _, err := store.PurgeExpiredTokens(now.Unix()); err != nil {
+				log.Printf("Blacklist purge error: %v\n", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,11 @@
+package database
+
+// CommentQuery selects a page of the comment threads of a post.
+// Threads are ordered oldest first, each with all of its replies.
+type CommentQuery struct {
+	PostID int
+	// Limit is the maximum number of top level comments, 0 means no limit
+	Limit int
+	// Offset is the number of top level comments to skip
+	Offset int
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/furkanpala/post-app/internal/core"
+)
+
+// addComment adds a comment into store and returns its id
+func addComment(t *testing.T, store Store, postID, parentID int, content string) int {
+	t.Helper()
+	comment := core.Comment{PostID: postID, ParentID: parentID, Content: content, User: "alice"}
+	if err := store.AddComment(&comment); err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+
+	return comment.ID
+}
+
+func TestCommentThreads(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		post := addPost(t, store, core.Post{Title: "Post", Content: "Content", User: "alice"})
+		other := addPost(t, store, core.Post{Title: "Other", Content: "Content", User: "alice"})
+
+		first := addComment(t, store, post.ID, 0, "first")
+		second := addComment(t, store, post.ID, 0, "second")
+		firstReply := addComment(t, store, post.ID, first, "reply to first")
+		third := addComment(t, store, post.ID, 0, "third")
+		nestedReply := addComment(t, store, post.ID, firstReply, "reply to the reply")
+		secondReply := addComment(t, store, post.ID, second, "reply to second")
+		addComment(t, store, other.ID, 0, "elsewhere")
+
+		if count, err := store.CountComments(post.ID); err != nil || count != 3 {
+			t.Fatalf("CountComments returned %d, %v, want 3 top level comments", count, err)
+		}
+
+		tests := []struct {
+			query CommentQuery
+			want  []int
+		}{
+			{CommentQuery{PostID: post.ID}, []int{first, second, third, firstReply, nestedReply, secondReply}},
+			{CommentQuery{PostID: post.ID, Limit: 2}, []int{first, second, firstReply, nestedReply, secondReply}},
+			{CommentQuery{PostID: post.ID, Limit: 2, Offset: 2}, []int{third}},
+			{CommentQuery{PostID: post.ID, Limit: 2, Offset: 4}, nil},
+		}
+
+		for _, test := range tests {
+			comments, err := store.ListComments(test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []int
+			for _, comment := range comments {
+				got = append(got, comment.ID)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ListComments(%+v) returned comments %v, want %v", test.query, got, test.want)
+			}
+		}
+	})
+}
+
+func TestEditAndDeleteComment(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		post := addPost(t, store, core.Post{Title: "Post", Content: "Content", User: "alice"})
+		id := addComment(t, store, post.ID, 0, "first")
+
+		if err := store.UpdateComment(&core.Comment{ID: id, Content: "edited"}); err != nil {
+			t.Fatal(err)
+		}
+		comment, err := store.FindComment(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if comment.Content != "edited" || comment.EditedAt == 0 {
+			t.Fatalf("edited comment is %+v", comment)
+		}
+
+		if err := store.DeleteComment(id); err != nil {
+			t.Fatal(err)
+		}
+		comment, err = store.FindComment(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if comment.Content != "" || comment.DeletedAt == 0 {
+			t.Fatalf("deleted comment is %+v, want it cleared and marked deleted", comment)
+		}
+		if err := store.UpdateComment(&core.Comment{ID: id, Content: "again"}); err != ErrNotFound {
+			t.Fatalf("UpdateComment of a deleted comment returned %v, want ErrNotFound", err)
+		}
+	})
+}
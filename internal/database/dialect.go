@@ -0,0 +1,78 @@
+package database
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+// dialect holds the differences between the SQL databases supported by SQLStore
+type dialect struct {
+	name   string
+	driver string
+	// numbered placeholders ($1, $2...) instead of "?"
+	numberedPlaceholders bool
+	// ddl rewrites the column types used in migrations
+	ddl *strings.Replacer
+}
+
+var sqliteDialect = dialect{
+	name:   "sqlite",
+	driver: "sqlite3",
+	ddl:    strings.NewReplacer(),
+}
+
+var postgresDialect = dialect{
+	name:                 "postgres",
+	driver:               "postgres",
+	numberedPlaceholders: true,
+	// Unix timestamps do not fit into 32 bit INTEGER of PostgreSQL after 2038
+	ddl: strings.NewReplacer(
+		"INTEGER PRIMARY KEY AUTOINCREMENT", "SERIAL PRIMARY KEY",
+		"INTEGER", "BIGINT",
+	),
+}
+
+// rebind replaces "?" placeholders of query with the ones dialect uses.
+// Queries must not contain "?" other than placeholders.
+func (d dialect) rebind(query string) string {
+	if !d.numberedPlaceholders {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(c)
+	}
+
+	return b.String()
+}
+
+// insert runs the INSERT statement query and returns the id of inserted row
+func (d dialect) insert(q querier, query string, args ...interface{}) (int64, error) {
+	if d.name == postgresDialect.name {
+		var id int64
+		err := q.QueryRow(d.rebind(query+" RETURNING id"), args...).Scan(&id)
+		return id, err
+	}
+
+	result, err := q.Exec(d.rebind(query), args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+// querier is implemented by both *sql.DB and *sql.Tx
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
@@ -0,0 +1,25 @@
+package database
+
+import "testing"
+
+func TestRebind(t *testing.T) {
+	query := "SELECT id FROM posts WHERE user = ? AND date < ? LIMIT ?"
+
+	if got := sqliteDialect.rebind(query); got != query {
+		t.Errorf("sqlite rebind = %q, want the query unchanged", got)
+	}
+
+	want := "SELECT id FROM posts WHERE user = $1 AND date < $2 LIMIT $3"
+	if got := postgresDialect.rebind(query); got != want {
+		t.Errorf("postgres rebind = %q, want %q", got, want)
+	}
+}
+
+func TestPostgresDDL(t *testing.T) {
+	ddl := "CREATE TABLE posts (id INTEGER PRIMARY KEY AUTOINCREMENT, date INTEGER NOT NULL)"
+	want := "CREATE TABLE posts (id SERIAL PRIMARY KEY, date BIGINT NOT NULL)"
+
+	if got := postgresDialect.ddl.Replace(ddl); got != want {
+		t.Errorf("postgres ddl = %q, want %q", got, want)
+	}
+}
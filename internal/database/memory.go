@@ -0,0 +1,618 @@
+package database
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/furkanpala/post-app/internal/core"
+	"github.com/furkanpala/post-app/internal/markdown"
+)
+
+var _ Store = (*MemoryStore)(nil)
+
+// MemoryStore is a Store that keeps everything in memory.
+// It is meant for tests and for embedding the server without a database file.
+type MemoryStore struct {
+	mu        sync.RWMutex
+	users     map[string]core.User
+	posts     []core.Post
+	lastID    int
+	revisions map[int][]core.Revision
+	blacklist map[string]int64
+
+	comments      []core.Comment
+	lastCommentID int
+	// commentThreads maps the id of a reply to the id of its top level comment
+	commentThreads map[int]int
+
+	// reactions maps the reactions to the times they were added
+	reactions map[reactionKey]int64
+
+	// changed maps the ids of the posts to the latest times they, their comments or their reactions changed
+	changed map[int]int64
+
+	attachments []core.Attachment
+
+	followings []following
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		users:     make(map[string]core.User),
+		revisions: make(map[int][]core.Revision),
+		blacklist: make(map[string]int64),
+
+		commentThreads: make(map[int]int),
+
+		reactions: make(map[reactionKey]int64),
+
+		changed: make(map[int]int64),
+	}
+}
+
+// Close does nothing, there is nothing to release
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// FindUser returns the user with given username, nil if there is no such user
+func (s *MemoryStore) FindUser(username string) (*core.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.users[username]
+	if !ok {
+		return nil, nil
+	}
+
+	return &user, nil
+}
+
+// AddUser adds the user into the store
+func (s *MemoryStore) AddUser(user *core.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user.JoinedAt = time.Now().Unix()
+	s.users[user.Username] = *user
+	return nil
+}
+
+// SetAdmin grants or revokes admin rights of the user
+func (s *MemoryStore) SetAdmin(username string, admin bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[username]
+	if !ok {
+		return ErrNotFound
+	}
+	user.Admin = admin
+	s.users[username] = user
+
+	return nil
+}
+
+// BlacklistToken adds the jti and expire time into the blacklist
+func (s *MemoryStore) BlacklistToken(jti string, expiresAt int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.blacklist[jti] = expiresAt
+	return nil
+}
+
+// FindJTI reports whether the given jti is in the blacklist
+func (s *MemoryStore) FindJTI(jti string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, found := s.blacklist[jti]
+	return found, nil
+}
+
+// PurgeExpiredTokens deletes the jtis whose token expired at or before now
+func (s *MemoryStore) PurgeExpiredTokens(now int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for jti, expiresAt := range s.blacklist {
+		if expiresAt <= now {
+			delete(s.blacklist, jti)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// CountPosts returns the number of posts selected by q, ignoring its paging fields
+func (s *MemoryStore) CountPosts(q PostQuery) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	count := 0
+	for _, post := range s.posts {
+		if visible(post) && s.matches(post, q) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// ListingState returns the count, the latest id and date of the posts selected by q,
+// the counts of their comments and reactions and the latest time they changed, ignoring its paging fields
+func (s *MemoryStore) ListingState(q PostQuery) (ListingState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var state ListingState
+	selected := make(map[int]bool)
+	for _, post := range s.posts {
+		if !s.matches(post, q) {
+			continue
+		}
+
+		// Posts which are deleted or not published are also considered,
+		// as deleting them or taking them out of the listing is a change too
+		if s.changed[post.ID] > state.LastModified {
+			state.LastModified = s.changed[post.ID]
+		}
+		if !visible(post) {
+			continue
+		}
+
+		state.Count++
+		if post.ID > state.LatestID {
+			state.LatestID = post.ID
+		}
+		if post.Date > state.LatestDate {
+			state.LatestDate = post.Date
+		}
+		selected[post.ID] = true
+	}
+
+	for _, comment := range s.comments {
+		if selected[comment.PostID] {
+			state.Comments++
+		}
+	}
+
+	for key := range s.reactions {
+		if selected[key.postID] {
+			state.Reactions++
+		}
+	}
+
+	return state, nil
+}
+
+// GetAllPosts returns all the posts, newest first
+func (s *MemoryStore) GetAllPosts() ([]core.Post, error) {
+	return s.ListPosts(PostQuery{})
+}
+
+// ListPosts returns the posts selected by q, newest first
+func (s *MemoryStore) ListPosts(q PostQuery) ([]core.Post, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var posts []core.Post
+	for _, post := range s.posts {
+		if !visible(post) {
+			continue
+		}
+		if q.After != nil && !q.After.before(post) {
+			continue
+		}
+		if q.PublishedAfter != nil && !q.PublishedAfter.after(post) {
+			continue
+		}
+		if !s.matches(post, q) {
+			continue
+		}
+		posts = append(posts, s.withCounts(post))
+	}
+
+	sort.Slice(posts, func(i, j int) bool {
+		a, b := posts[i], posts[j]
+		switch q.Sort {
+		case SortOldest:
+			a, b = b, a
+		case SortMostCommented:
+			if a.CommentCount != b.CommentCount {
+				return a.CommentCount > b.CommentCount
+			}
+		}
+		if a.Date != b.Date {
+			return a.Date > b.Date
+		}
+		return a.ID > b.ID
+	})
+
+	return paginate(posts, q.Limit, q.Offset), nil
+}
+
+// matches reports whether post is selected by the filters of q
+// Caller must hold s.mu.
+func (s *MemoryStore) matches(post core.Post, q PostQuery) bool {
+	if q.FollowedBy != "" && !s.follows(q.FollowedBy, post.User) {
+		return false
+	}
+
+	if q.Since != 0 && post.Date < q.Since {
+		return false
+	}
+	if q.Until != 0 && post.Date >= q.Until {
+		return false
+	}
+	if q.Title != "" && !strings.Contains(strings.ToLower(post.Title), strings.ToLower(q.Title)) {
+		return false
+	}
+
+	return (q.Author == "" || post.User == q.Author) && hasTags(post, q.Tags)
+}
+
+// visible reports whether post is listed publicly
+func visible(post core.Post) bool {
+	return post.DeletedAt == 0 && post.Status == core.PostPublished
+}
+
+// paginate returns the part of posts selected by limit and offset
+func paginate(posts []core.Post, limit, offset int) []core.Post {
+	if offset >= len(posts) {
+		return nil
+	}
+	posts = posts[offset:]
+
+	if limit > 0 && limit < len(posts) {
+		posts = posts[:limit]
+	}
+
+	return posts
+}
+
+// SearchPosts returns the posts containing every term of q, newest first.
+// Unlike the databases it does not rank the results.
+func (s *MemoryStore) SearchPosts(q SearchQuery) ([]core.SearchResult, error) {
+	terms := parseSearchQuery(q.Text)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	posts, err := s.GetAllPosts()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []core.SearchResult
+	for _, post := range posts {
+		if q.Author != "" && post.User != q.Author {
+			continue
+		}
+		if !matchesTerms(post.Title+"\n"+post.Content, terms) {
+			continue
+		}
+
+		results = append(results, core.SearchResult{
+			Post:           post,
+			TitleHighlight: renderHighlight(highlightTerms(post.Title, terms)),
+			Snippet:        renderHighlight(highlightTerms(post.Content, terms)),
+		})
+	}
+
+	if q.Offset >= len(results) {
+		return nil, nil
+	}
+	results = results[q.Offset:]
+	if q.Limit > 0 && q.Limit < len(results) {
+		results = results[:q.Limit]
+	}
+
+	return results, nil
+}
+
+// AddPost adds a post into the store
+func (s *MemoryStore) AddPost(post *core.Post) error {
+	contentHTML, err := markdown.Render(post.Content)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastID++
+	post.ID = s.lastID
+	if err := s.attach(*post); err != nil {
+		s.lastID--
+		post.ID = 0
+		return err
+	}
+	tags := make([]string, len(post.Tags))
+	copy(tags, post.Tags)
+	sort.Strings(tags)
+
+	if post.Status == "" {
+		post.Status = core.PostPublished
+	}
+
+	s.posts = append(s.posts, core.Post{
+		ID:        post.ID,
+		Title:     post.Title,
+		Content:   post.Content,
+		User:      post.User,
+		Date:      time.Now().Unix(),
+		Version:   1,
+		Tags:      tags,
+		Status:    post.Status,
+		PublishAt: post.PublishAt,
+
+		ContentHTML: contentHTML,
+	})
+	post.ContentHTML = contentHTML
+	post.Date = s.posts[len(s.posts)-1].Date
+	post.Version = 1
+	s.touch(post.ID, post.Date)
+
+	return nil
+}
+
+// FindPost returns the post with given id, ErrNotFound if there is no such post
+func (s *MemoryStore) FindPost(id int) (*core.Post, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := s.postIndex(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+
+	post := s.withCounts(s.posts[i])
+	return &post, nil
+}
+
+// UpdatePost saves title, content and status of post if the stored post is still at version
+func (s *MemoryStore) UpdatePost(post *core.Post, version int) error {
+	contentHTML, err := markdown.Render(post.Content)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.postIndex(post.ID)
+	if i < 0 || s.posts[i].DeletedAt != 0 {
+		return ErrNotFound
+	}
+
+	stored := &s.posts[i]
+	if stored.Version != version {
+		return ErrVersionConflict
+	}
+
+	revision := core.Revision{
+		PostID:  stored.ID,
+		Number:  stored.Version,
+		Title:   stored.Title,
+		Content: stored.Content,
+		Date:    stored.Date,
+	}
+	if stored.EditedAt != 0 {
+		revision.Date = stored.EditedAt
+	}
+	s.revisions[stored.ID] = append(s.revisions[stored.ID], revision)
+
+	stored.Title = post.Title
+	stored.Content = post.Content
+	stored.ContentHTML = contentHTML
+	stored.EditedAt = time.Now().Unix()
+	stored.Version++
+
+	// Date of a post becomes the publishing time when it is published
+	if stored.Status != core.PostPublished && post.Status == core.PostPublished {
+		stored.Date = stored.EditedAt
+	}
+	stored.Status = post.Status
+	stored.PublishAt = post.PublishAt
+	s.touch(stored.ID, stored.EditedAt)
+
+	post.ContentHTML = contentHTML
+	post.Date = stored.Date
+	post.EditedAt = stored.EditedAt
+	post.Version = stored.Version
+	return nil
+}
+
+// DeletePost moves the post into the trash
+func (s *MemoryStore) DeletePost(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.postIndex(id)
+	if i < 0 || s.posts[i].DeletedAt != 0 {
+		return ErrNotFound
+	}
+	s.posts[i].DeletedAt = time.Now().Unix()
+	s.touch(id, s.posts[i].DeletedAt)
+
+	return nil
+}
+
+// RestorePost takes the post out of the trash
+func (s *MemoryStore) RestorePost(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.postIndex(id)
+	if i < 0 || s.posts[i].DeletedAt == 0 {
+		return ErrNotFound
+	}
+	s.posts[i].DeletedAt = 0
+	s.touch(id, time.Now().Unix())
+
+	return nil
+}
+
+// ListTrash returns the deleted posts of the user, most recently deleted first
+func (s *MemoryStore) ListTrash(username string) ([]core.Post, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var posts []core.Post
+	for _, post := range s.posts {
+		if post.User == username && post.DeletedAt != 0 {
+			posts = append(posts, s.withCounts(post))
+		}
+	}
+
+	sort.Slice(posts, func(i, j int) bool {
+		if posts[i].DeletedAt != posts[j].DeletedAt {
+			return posts[i].DeletedAt > posts[j].DeletedAt
+		}
+		return posts[i].ID > posts[j].ID
+	})
+
+	return posts, nil
+}
+
+// touch records now as the time the post with given id, its comments or its reactions last changed.
+// Caller must hold s.mu.
+func (s *MemoryStore) touch(postID int, now int64) {
+	s.changed[postID] = now
+}
+
+// PurgeDeletedPosts removes the posts deleted at or before the given unix time
+// and their attachments, returns the ids of the removed attachments
+func (s *MemoryStore) PurgeDeletedPosts(before int64) (int64, []string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	var attachmentIDs []string
+	posts := s.posts[:0]
+	for _, post := range s.posts {
+		if post.DeletedAt != 0 && post.DeletedAt <= before {
+			delete(s.revisions, post.ID)
+			delete(s.changed, post.ID)
+			s.purgeComments(post.ID)
+			s.purgeReactions(post.ID)
+			attachmentIDs = append(attachmentIDs, s.purgeAttachments(post.ID)...)
+			purged++
+			continue
+		}
+		posts = append(posts, post)
+	}
+	s.posts = posts
+
+	return purged, attachmentIDs, nil
+}
+
+// ListRevisions returns the versions of the post replaced by edits, oldest first
+func (s *MemoryStore) ListRevisions(postID int) ([]core.Revision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	revisions := make([]core.Revision, len(s.revisions[postID]))
+	copy(revisions, s.revisions[postID])
+
+	return revisions, nil
+}
+
+// FindRevision returns the nth version of the post, ErrNotFound if there is no such revision
+func (s *MemoryStore) FindRevision(postID, n int) (*core.Revision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, revision := range s.revisions[postID] {
+		if revision.Number == n {
+			return &revision, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// withCounts returns a copy of post with its counts and attachments filled in.
+// Caller must hold s.mu.
+func (s *MemoryStore) withCounts(post core.Post) core.Post {
+	post.CommentCount = s.commentCount(post.ID)
+	post.AttachmentIDs = s.attachmentIDs(post.ID)
+	if post.Tags != nil {
+		post.Tags = append([]string(nil), post.Tags...)
+	}
+	return post
+}
+
+// purgeComments removes the comments of the post.
+// Caller must hold s.mu for writing.
+func (s *MemoryStore) purgeComments(postID int) {
+	comments := s.comments[:0]
+	for _, comment := range s.comments {
+		if comment.PostID == postID {
+			delete(s.commentThreads, comment.ID)
+			continue
+		}
+		comments = append(comments, comment)
+	}
+	s.comments = comments
+}
+
+// postIndex returns the index of the post with given id in s.posts, -1 if there is no such post.
+// Caller must hold s.mu.
+func (s *MemoryStore) postIndex(id int) int {
+	for i := range s.posts {
+		if s.posts[i].ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// ListDrafts returns the drafts and scheduled posts of the user which are not deleted,
+// most recently created first
+func (s *MemoryStore) ListDrafts(username string) ([]core.Post, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var posts []core.Post
+	for _, post := range s.posts {
+		if post.User == username && post.DeletedAt == 0 && post.Status != core.PostPublished {
+			posts = append(posts, s.withCounts(post))
+		}
+	}
+
+	sort.Slice(posts, func(i, j int) bool {
+		return posts[i].ID > posts[j].ID
+	})
+
+	return posts, nil
+}
+
+// PublishScheduledPosts publishes the scheduled posts which are not deleted
+// and whose publishing time is at or before now, and returns them dated now
+func (s *MemoryStore) PublishScheduledPosts(now int64) ([]core.Post, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var published []core.Post
+	for i := range s.posts {
+		post := &s.posts[i]
+		if post.Status == core.PostScheduled && post.PublishAt <= now && post.DeletedAt == 0 {
+			post.Status = core.PostPublished
+			post.Date = now
+			post.PublishAt = 0
+			s.touch(post.ID, now)
+			published = append(published, s.withCounts(*post))
+		}
+	}
+
+	return published, nil
+}
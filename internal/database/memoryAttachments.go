@@ -0,0 +1,115 @@
+package database
+
+import (
+	"github.com/furkanpala/post-app/internal/core"
+)
+
+// AddAttachment adds an attachment, which is not attached to any post yet, into the store
+func (s *MemoryStore) AddAttachment(a *core.Attachment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *a
+	stored.PostID = 0
+	s.attachments = append(s.attachments, stored)
+
+	return nil
+}
+
+// FindAttachment returns the attachment with given id, ErrNotFound if there is no such attachment
+func (s *MemoryStore) FindAttachment(id string) (*core.Attachment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := s.attachmentIndex(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+
+	attachment := s.attachments[i]
+	return &attachment, nil
+}
+
+// PurgeUnattachedAttachments removes the attachments uploaded at or before the given unix time
+// which are not attached to any post and returns their ids
+func (s *MemoryStore) PurgeUnattachedAttachments(before int64) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ids []string
+	attachments := s.attachments[:0]
+	for _, attachment := range s.attachments {
+		if attachment.PostID == 0 && attachment.Date <= before {
+			ids = append(ids, attachment.ID)
+			continue
+		}
+		attachments = append(attachments, attachment)
+	}
+	s.attachments = attachments
+
+	return ids, nil
+}
+
+// attach attaches the attachments with given ids to the post.
+// Every attachment must be uploaded by the sender of the post and not attached to any post,
+// otherwise none of them is attached.
+// Caller must hold s.mu for writing.
+func (s *MemoryStore) attach(post core.Post) error {
+	indexes := make([]int, 0, len(post.AttachmentIDs))
+	for _, id := range post.AttachmentIDs {
+		i := s.attachmentIndex(id)
+		if i < 0 || s.attachments[i].User != post.User || s.attachments[i].PostID != 0 {
+			return ErrInvalidAttachment
+		}
+		indexes = append(indexes, i)
+	}
+
+	for _, i := range indexes {
+		s.attachments[i].PostID = post.ID
+	}
+
+	return nil
+}
+
+// purgeAttachments removes the attachments of the post and returns their ids.
+// Caller must hold s.mu for writing.
+func (s *MemoryStore) purgeAttachments(postID int) []string {
+	var ids []string
+	attachments := s.attachments[:0]
+	for _, attachment := range s.attachments {
+		if attachment.PostID == postID {
+			ids = append(ids, attachment.ID)
+			continue
+		}
+		attachments = append(attachments, attachment)
+	}
+	s.attachments = attachments
+
+	return ids
+}
+
+// attachmentIDs returns the ids of the attachments of the post, in upload order.
+// Caller must hold s.mu.
+func (s *MemoryStore) attachmentIDs(postID int) []string {
+	var ids []string
+	for _, attachment := range s.attachments {
+		if attachment.PostID == postID {
+			ids = append(ids, attachment.ID)
+		}
+	}
+
+	return ids
+}
+
+// attachmentIndex returns the index of the attachment with given id in s.attachments,
+// -1 if there is no such attachment.
+// Caller must hold s.mu.
+func (s *MemoryStore) attachmentIndex(id string) int {
+	for i := range s.attachments {
+		if s.attachments[i].ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,158 @@
+package database
+
+import (
+	"time"
+
+	"github.com/furkanpala/post-app/internal/core"
+)
+
+// ListComments returns the top level comments selected by q followed by all of their replies
+func (s *MemoryStore) ListComments(q CommentQuery) ([]core.Comment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var roots []core.Comment
+	for _, comment := range s.comments {
+		if comment.PostID == q.PostID && comment.ParentID == 0 {
+			roots = append(roots, comment)
+		}
+	}
+
+	if q.Offset >= len(roots) {
+		return nil, nil
+	}
+	roots = roots[q.Offset:]
+	if q.Limit > 0 && q.Limit < len(roots) {
+		roots = roots[:q.Limit]
+	}
+
+	inPage := make(map[int]bool, len(roots))
+	for _, root := range roots {
+		inPage[root.ID] = true
+	}
+
+	comments := roots
+	for _, comment := range s.comments {
+		if comment.ParentID != 0 && inPage[s.commentThreads[comment.ID]] {
+			comments = append(comments, comment)
+		}
+	}
+
+	return comments, nil
+}
+
+// CountComments returns the number of top level comments of the post
+func (s *MemoryStore) CountComments(postID int) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	count := 0
+	for _, comment := range s.comments {
+		if comment.PostID == postID && comment.ParentID == 0 {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// FindComment returns the comment with given id, ErrNotFound if there is no such comment
+func (s *MemoryStore) FindComment(id int) (*core.Comment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := s.commentIndex(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+
+	comment := s.comments[i]
+	return &comment, nil
+}
+
+// AddComment adds the comment into the store and sets its ID and date
+func (s *MemoryStore) AddComment(comment *core.Comment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if comment.ParentID != 0 && s.commentIndex(comment.ParentID) < 0 {
+		return ErrNotFound
+	}
+
+	s.lastCommentID++
+	comment.ID = s.lastCommentID
+	comment.Date = time.Now().Unix()
+
+	if comment.ParentID != 0 {
+		// Thread of a top level comment is itself
+		thread, ok := s.commentThreads[comment.ParentID]
+		if !ok {
+			thread = comment.ParentID
+		}
+		s.commentThreads[comment.ID] = thread
+	}
+
+	s.comments = append(s.comments, *comment)
+	s.touch(comment.PostID, comment.Date)
+	return nil
+}
+
+// UpdateComment saves the content of the comment, ErrNotFound if the comment is deleted
+func (s *MemoryStore) UpdateComment(comment *core.Comment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.commentIndex(comment.ID)
+	if i < 0 || s.comments[i].DeletedAt != 0 {
+		return ErrNotFound
+	}
+
+	s.comments[i].Content = comment.Content
+	s.comments[i].EditedAt = time.Now().Unix()
+	comment.EditedAt = s.comments[i].EditedAt
+	s.touch(s.comments[i].PostID, comment.EditedAt)
+
+	return nil
+}
+
+// DeleteComment clears the content of the comment and marks it deleted
+func (s *MemoryStore) DeleteComment(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.commentIndex(id)
+	if i < 0 || s.comments[i].DeletedAt != 0 {
+		return ErrNotFound
+	}
+
+	s.comments[i].Content = ""
+	s.comments[i].DeletedAt = time.Now().Unix()
+	s.touch(s.comments[i].PostID, s.comments[i].DeletedAt)
+
+	return nil
+}
+
+// commentIndex returns the index of the comment with given id in s.comments, -1 if there is no such comment.
+// Caller must hold s.mu.
+func (s *MemoryStore) commentIndex(id int) int {
+	for i := range s.comments {
+		if s.comments[i].ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// commentCount returns the number of comments of the post which are not deleted.
+// Caller must hold s.mu.
+func (s *MemoryStore) commentCount(postID int) int {
+	count := 0
+	for _, comment := range s.comments {
+		if comment.PostID == postID && comment.DeletedAt == 0 {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,111 @@
+package database
+
+import "time"
+
+// following is a user following another
+type following struct {
+	follower string
+	followee string
+	date     int64
+}
+
+// Follow makes follower follow followee, does nothing if it already does
+func (s *MemoryStore) Follow(follower, followee string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.follows(follower, followee) {
+		return nil
+	}
+	s.followings = append(s.followings, following{follower, followee, time.Now().Unix()})
+
+	return nil
+}
+
+// Unfollow makes follower stop following followee
+func (s *MemoryStore) Unfollow(follower, followee string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	followings := s.followings[:0]
+	for _, f := range s.followings {
+		if f.follower != follower || f.followee != followee {
+			followings = append(followings, f)
+		}
+	}
+	s.followings = followings
+
+	return nil
+}
+
+// ListFollowers returns the users following the user, most recent first
+func (s *MemoryStore) ListFollowers(username string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var followers []string
+	for i := len(s.followings) - 1; i >= 0; i-- {
+		if s.followings[i].followee == username {
+			followers = append(followers, s.followings[i].follower)
+		}
+	}
+
+	return followers, nil
+}
+
+// ListFollowing returns the users followed by the user, most recent first
+func (s *MemoryStore) ListFollowing(username string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var followees []string
+	for i := len(s.followings) - 1; i >= 0; i-- {
+		if s.followings[i].follower == username {
+			followees = append(followees, s.followings[i].followee)
+		}
+	}
+
+	return followees, nil
+}
+
+// CountFollowers returns the number of users following the user
+func (s *MemoryStore) CountFollowers(username string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	count := 0
+	for _, f := range s.followings {
+		if f.followee == username {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// CountFollowing returns the number of users followed by the user
+func (s *MemoryStore) CountFollowing(username string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	count := 0
+	for _, f := range s.followings {
+		if f.follower == username {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// follows reports whether follower follows followee.
+// Caller must hold s.mu.
+func (s *MemoryStore) follows(follower, followee string) bool {
+	for _, f := range s.followings {
+		if f.follower == follower && f.followee == followee {
+			return true
+		}
+	}
+
+	return false
+}
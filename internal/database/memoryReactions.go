@@ -0,0 +1,84 @@
+package database
+
+import (
+	"sort"
+	"time"
+
+	"github.com/furkanpala/post-app/internal/core"
+)
+
+// reactionKey identifies a reaction of a user to a post
+type reactionKey struct {
+	postID   int
+	username string
+	reaction string
+}
+
+// AddReaction adds the reaction of the user to the post, does nothing if it is already added
+func (s *MemoryStore) AddReaction(postID int, username, reaction string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := reactionKey{postID, username, reaction}
+	if _, ok := s.reactions[key]; !ok {
+		s.reactions[key] = time.Now().Unix()
+		s.touch(postID, s.reactions[key])
+	}
+	return nil
+}
+
+// RemoveReaction removes the reaction of the user from the post
+func (s *MemoryStore) RemoveReaction(postID int, username, reaction string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := reactionKey{postID, username, reaction}
+	if _, ok := s.reactions[key]; ok {
+		delete(s.reactions, key)
+		s.touch(postID, time.Now().Unix())
+	}
+	return nil
+}
+
+// LoadReactions fills in the reaction counts of the posts and the reactions the user gave to them
+func (s *MemoryStore) LoadReactions(posts []core.Post, username string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	index := make(map[int]int, len(posts))
+	for i, post := range posts {
+		index[post.ID] = i
+	}
+
+	for key := range s.reactions {
+		i, ok := index[key.postID]
+		if !ok {
+			continue
+		}
+
+		post := &posts[i]
+		if post.Reactions == nil {
+			post.Reactions = make(map[string]int)
+		}
+		post.Reactions[key.reaction]++
+		if username != "" && key.username == username {
+			post.Reacted = append(post.Reacted, key.reaction)
+		}
+	}
+
+	for i := range posts {
+		sort.Strings(posts[i].Reacted)
+	}
+
+	return nil
+}
+
+// purgeReactions removes the reactions to the post.
+// Caller must hold s.mu for writing.
+func (s *MemoryStore) purgeReactions(postID int) {
+	for key := range s.reactions {
+		if key.postID == postID {
+			delete(s.reactions, key)
+		}
+	}
+}
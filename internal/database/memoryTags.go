@@ -0,0 +1,56 @@
+package database
+
+import (
+	"sort"
+
+	"github.com/furkanpala/post-app/internal/core"
+)
+
+// ListTags returns the tags of the posts which are not deleted
+// with the number of posts tagged with them, most used first
+func (s *MemoryStore) ListTags() ([]core.Tag, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, post := range s.posts {
+		if !visible(post) {
+			continue
+		}
+		for _, tag := range post.Tags {
+			counts[tag]++
+		}
+	}
+
+	var tags []core.Tag
+	for name, count := range counts {
+		tags = append(tags, core.Tag{Name: name, Count: count})
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Name < tags[j].Name
+	})
+
+	return tags, nil
+}
+
+// hasTags reports whether post is tagged with all of the tags
+func hasTags(post core.Post, tags []string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, postTag := range post.Tags {
+			if postTag == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,175 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// MigrationStatus tells whether a migration is applied to the database
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator is implemented by the stores whose schema is versioned
+type Migrator interface {
+	// MigrateUp applies every pending migration in order.
+	MigrateUp() error
+	// MigrateDown reverts the last n applied migrations.
+	MigrateDown(n int) error
+	// MigrationStatus lists all known migrations and whether they are applied.
+	MigrationStatus() ([]MigrationStatus, error)
+}
+
+// createSchemaVersionTable function creates schema_version table if it does not exist
+// schema_version table stores the versions of applied migrations
+func createSchemaVersionTable(db *sql.DB, d dialect) error {
+	_, err := db.Exec(d.ddl.Replace(`CREATE TABLE IF NOT EXISTS "schema_version" (
+		"version"	INTEGER,
+		"name"	TEXT NOT NULL,
+		"applied_at"	INTEGER NOT NULL,
+		PRIMARY KEY("version")
+	);`))
+
+	return err
+}
+
+// appliedVersions returns the applied migration versions with their apply time
+func appliedVersions(db *sql.DB, d dialect) (map[int]int64, error) {
+	if err := createSchemaVersionTable(db, d); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query("SELECT version, applied_at FROM schema_version")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]int64)
+	for rows.Next() {
+		var version int
+		var appliedAt int64
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+// migrateUp applies the pending migrations in order, each one in its own transaction
+func migrateUp(db *sql.DB, d dialect, migrations []Migration) error {
+	applied, err := appliedVersions(db, d)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		if m.SQLiteModule != "" && d.name != postgresDialect.name {
+			available, err := hasModule(db, m.SQLiteModule)
+			if err != nil {
+				return err
+			}
+			if !available {
+				log.Printf("Skipping migration %d (%s): SQLite is built without %s\n", m.Version, m.Name, m.SQLiteModule)
+				continue
+			}
+		}
+
+		err := inTx(db, func(tx *sql.Tx) error {
+			if _, err := tx.Exec(m.up(d)); err != nil {
+				return err
+			}
+			_, err := tx.Exec(d.rebind("INSERT INTO schema_version(version,name,applied_at) values (?,?,?)"),
+				m.Version, m.Name, time.Now().Unix())
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d (%s): %v", m.Version, m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// hasModule reports whether SQLite is built with the module
+func hasModule(db *sql.DB, module string) (bool, error) {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM pragma_module_list WHERE name = ?", module).Scan(&count)
+
+	return count > 0, err
+}
+
+// migrateDown reverts the last n applied migrations, newest first
+func migrateDown(db *sql.DB, d dialect, migrations []Migration, n int) error {
+	applied, err := appliedVersions(db, d)
+	if err != nil {
+		return err
+	}
+
+	for i := len(migrations) - 1; i >= 0 && n > 0; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+
+		err := inTx(db, func(tx *sql.Tx) error {
+			if _, err := tx.Exec(m.down(d)); err != nil {
+				return err
+			}
+			_, err := tx.Exec(d.rebind("DELETE FROM schema_version WHERE version = ?"), m.Version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d (%s): %v", m.Version, m.Name, err)
+		}
+		n--
+	}
+
+	return nil
+}
+
+// migrationStatus lists the migrations and whether they are applied
+func migrationStatus(db *sql.DB, d dialect, migrations []Migration) ([]MigrationStatus, error) {
+	applied, err := appliedVersions(db, d)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{Migration: m}
+		if appliedAt, ok := applied[m.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = time.Unix(appliedAt, 0)
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// inTx runs fn inside a transaction.
+// The transaction is committed if fn succeeds, rolled back otherwise.
+func inTx(db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
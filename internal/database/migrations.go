@@ -0,0 +1,308 @@
+package database
+
+// Migration is a single versioned change of the database schema.
+// Up applies the change, Down reverts it.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+	// PostgresUp and PostgresDown replace Up and Down on PostgreSQL
+	// for the changes which cannot be written the same way for both databases
+	PostgresUp   string
+	PostgresDown string
+	// SQLiteModule, if not empty, is the module SQLite must be built with to apply the migration.
+	// Without it the migration is skipped and left pending.
+	SQLiteModule string
+}
+
+// up returns the statements which apply m on the dialect d
+func (m Migration) up(d dialect) string {
+	if d.name == postgresDialect.name && m.PostgresUp != "" {
+		return m.PostgresUp
+	}
+	return d.ddl.Replace(m.Up)
+}
+
+// down returns the statements which revert m on the dialect d
+func (m Migration) down(d dialect) string {
+	if d.name == postgresDialect.name && m.PostgresDown != "" {
+		return m.PostgresDown
+	}
+	return d.ddl.Replace(m.Down)
+}
+
+// migrations holds every schema change in the order they are applied.
+// Applied migrations must never be edited, add a new one instead.
+// Statements are written for SQLite, column types are rewritten
+// for other dialects when the migration is applied.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create users table",
+		// users table stores the username and password
+		Up: `CREATE TABLE IF NOT EXISTS "users" (
+			"username"	TEXT,
+			"password"	TEXT NOT NULL,
+			PRIMARY KEY("username")
+		);`,
+		Down: `DROP TABLE "users";`,
+	},
+	{
+		Version: 2,
+		Name:    "create posts table",
+		// posts table stores the context and writer of posts
+		Up: `CREATE TABLE IF NOT EXISTS "posts" (
+			"id"	INTEGER PRIMARY KEY AUTOINCREMENT,
+			"title"	TEXT NOT NULL,
+			"content"	TEXT NOT NULL,
+			"sent_by"	TEXT NOT NULL,
+			"date_added"	INTEGER NOT NULL,
+			FOREIGN KEY("sent_by") REFERENCES "users"("username")
+		);`,
+		Down: `DROP TABLE "posts";`,
+	},
+	{
+		Version: 3,
+		Name:    "create blacklist table",
+		// blacklist table is used for revoking refresh tokens
+		Up: `CREATE TABLE IF NOT EXISTS "blacklist" (
+			"jti"	TEXT,
+			"expiresAt"	INTEGER NOT NULL,
+			PRIMARY KEY("jti")
+		);`,
+		Down: `DROP TABLE "blacklist";`,
+	},
+	{
+		Version: 4,
+		Name:    "index posts by date",
+		// posts are listed newest first, both with offset and keyset pagination
+		Up:   `CREATE INDEX "posts_date_added_id" ON "posts" ("date_added" DESC, "id" DESC);`,
+		Down: `DROP INDEX "posts_date_added_id";`,
+	},
+	{
+		Version: 5,
+		Name:    "full-text search on posts",
+		// posts_fts indexes title and content of posts,
+		// triggers keep it in sync with posts table.
+		// It is skipped on SQLite built without FTS5, search falls back to LIKE then, see README.
+		Up: `CREATE VIRTUAL TABLE "posts_fts" USING fts5(
+			"title",
+			"content",
+			content='posts',
+			content_rowid='id'
+		);
+		INSERT INTO "posts_fts"("posts_fts") VALUES ('rebuild');
+		CREATE TRIGGER "posts_fts_insert" AFTER INSERT ON "posts" BEGIN
+			INSERT INTO "posts_fts"(rowid, "title", "content") VALUES (new."id", new."title", new."content");
+		END;
+		CREATE TRIGGER "posts_fts_delete" AFTER DELETE ON "posts" BEGIN
+			INSERT INTO "posts_fts"("posts_fts", rowid, "title", "content") VALUES ('delete', old."id", old."title", old."content");
+		END;
+		CREATE TRIGGER "posts_fts_update" AFTER UPDATE OF "title", "content" ON "posts" BEGIN
+			INSERT INTO "posts_fts"("posts_fts", rowid, "title", "content") VALUES ('delete', old."id", old."title", old."content");
+			INSERT INTO "posts_fts"(rowid, "title", "content") VALUES (new."id", new."title", new."content");
+		END;`,
+		Down: `DROP TRIGGER "posts_fts_update";
+		DROP TRIGGER "posts_fts_delete";
+		DROP TRIGGER "posts_fts_insert";
+		DROP TABLE "posts_fts";`,
+		// PostgreSQL keeps the search vector in a generated column
+		PostgresUp: `ALTER TABLE "posts" ADD COLUMN "search_vector" tsvector GENERATED ALWAYS AS (
+			setweight(to_tsvector('english', "title"), 'A') || setweight(to_tsvector('english', "content"), 'B')
+		) STORED;
+		CREATE INDEX "posts_search_vector" ON "posts" USING GIN ("search_vector");`,
+		PostgresDown: `DROP INDEX "posts_search_vector";
+		ALTER TABLE "posts" DROP COLUMN "search_vector";`,
+		SQLiteModule: "fts5",
+	},
+	{
+		Version: 6,
+		Name:    "index blacklist by expire time",
+		// expired jtis are purged periodically
+		Up:   `CREATE INDEX "blacklist_expires_at" ON "blacklist" ("expiresAt");`,
+		Down: `DROP INDEX "blacklist_expires_at";`,
+	},
+	{
+		Version: 7,
+		Name:    "post edits and admin users",
+		// version of a post is incremented on every edit to reject stale writes
+		Up: `ALTER TABLE "posts" ADD COLUMN "edited_at" INTEGER;
+		ALTER TABLE "posts" ADD COLUMN "version" INTEGER NOT NULL DEFAULT 1;
+		ALTER TABLE "users" ADD COLUMN "is_admin" BOOLEAN NOT NULL DEFAULT FALSE;`,
+		Down: `ALTER TABLE "users" DROP COLUMN "is_admin";
+		ALTER TABLE "posts" DROP COLUMN "version";
+		ALTER TABLE "posts" DROP COLUMN "edited_at";`,
+	},
+	{
+		Version: 8,
+		Name:    "soft delete posts",
+		// deleted posts stay in the trash until they are purged
+		Up: `ALTER TABLE "posts" ADD COLUMN "deleted_at" INTEGER;
+		CREATE INDEX "posts_deleted_at" ON "posts" ("deleted_at");`,
+		Down: `DROP INDEX "posts_deleted_at";
+		ALTER TABLE "posts" DROP COLUMN "deleted_at";`,
+	},
+	{
+		Version: 9,
+		Name:    "create post revisions table",
+		// post_revisions table stores the versions of posts replaced by edits
+		Up: `CREATE TABLE "post_revisions" (
+			"post_id"	INTEGER NOT NULL,
+			"revision"	INTEGER NOT NULL,
+			"title"	TEXT NOT NULL,
+			"content"	TEXT NOT NULL,
+			"date_added"	INTEGER NOT NULL,
+			PRIMARY KEY("post_id", "revision"),
+			FOREIGN KEY("post_id") REFERENCES "posts"("id")
+		);`,
+		Down: `DROP TABLE "post_revisions";`,
+	},
+	{
+		Version: 10,
+		Name:    "create comments table",
+		// comments table stores the comments on posts and the replies to comments.
+		// thread_id is the id of the top level comment a reply belongs to,
+		// NULL for top level comments.
+		Up: `CREATE TABLE "comments" (
+			"id"	INTEGER PRIMARY KEY AUTOINCREMENT,
+			"post_id"	INTEGER NOT NULL,
+			"parent_id"	INTEGER,
+			"thread_id"	INTEGER,
+			"content"	TEXT NOT NULL,
+			"sent_by"	TEXT NOT NULL,
+			"date_added"	INTEGER NOT NULL,
+			"edited_at"	INTEGER,
+			"deleted_at"	INTEGER,
+			FOREIGN KEY("post_id") REFERENCES "posts"("id"),
+			FOREIGN KEY("parent_id") REFERENCES "comments"("id"),
+			FOREIGN KEY("sent_by") REFERENCES "users"("username")
+		);
+		CREATE INDEX "comments_post_id" ON "comments" ("post_id", "parent_id");
+		CREATE INDEX "comments_thread_id" ON "comments" ("thread_id");`,
+		Down: `DROP TABLE "comments";`,
+	},
+	{
+		Version: 11,
+		Name:    "create post reactions table",
+		// post_reactions table stores the reactions of users to posts,
+		// a user can give each type of reaction once to a post
+		Up: `CREATE TABLE "post_reactions" (
+			"post_id"	INTEGER NOT NULL,
+			"username"	TEXT NOT NULL,
+			"reaction"	TEXT NOT NULL,
+			"date_added"	INTEGER NOT NULL,
+			PRIMARY KEY("post_id", "username", "reaction"),
+			FOREIGN KEY("post_id") REFERENCES "posts"("id"),
+			FOREIGN KEY("username") REFERENCES "users"("username")
+		);`,
+		Down: `DROP TABLE "post_reactions";`,
+	},
+	{
+		Version: 12,
+		Name:    "create tags tables",
+		// tags table stores the names of tags, post_tags table which posts are tagged with them
+		Up: `CREATE TABLE "tags" (
+			"id"	INTEGER PRIMARY KEY AUTOINCREMENT,
+			"name"	TEXT NOT NULL UNIQUE
+		);
+		CREATE TABLE "post_tags" (
+			"post_id"	INTEGER NOT NULL,
+			"tag_id"	INTEGER NOT NULL,
+			PRIMARY KEY("post_id", "tag_id"),
+			FOREIGN KEY("post_id") REFERENCES "posts"("id"),
+			FOREIGN KEY("tag_id") REFERENCES "tags"("id")
+		);
+		CREATE INDEX "post_tags_tag_id" ON "post_tags" ("tag_id");`,
+		Down: `DROP TABLE "post_tags";
+		DROP TABLE "tags";`,
+	},
+	{
+		Version: 13,
+		Name:    "cache rendered post content",
+		// content_html holds the sanitized HTML rendered from the Markdown content,
+		// NULL until the post is rendered
+		Up:   `ALTER TABLE "posts" ADD COLUMN "content_html" TEXT;`,
+		Down: `ALTER TABLE "posts" DROP COLUMN "content_html";`,
+	},
+	{
+		Version: 14,
+		Name:    "create attachments table",
+		// attachments table stores the images uploaded by users,
+		// id is also the key of the file in the blob store.
+		// post_id is NULL until the attachment is attached to a post.
+		Up: `CREATE TABLE "attachments" (
+			"id"	TEXT NOT NULL,
+			"content_type"	TEXT NOT NULL,
+			"size"	INTEGER NOT NULL,
+			"width"	INTEGER NOT NULL,
+			"height"	INTEGER NOT NULL,
+			"uploaded_by"	TEXT NOT NULL,
+			"date_added"	INTEGER NOT NULL,
+			"post_id"	INTEGER,
+			PRIMARY KEY("id"),
+			FOREIGN KEY("uploaded_by") REFERENCES "users"("username"),
+			FOREIGN KEY("post_id") REFERENCES "posts"("id")
+		);
+		CREATE INDEX "attachments_post_id" ON "attachments" ("post_id");`,
+		Down: `DROP TABLE "attachments";`,
+	},
+	{
+		Version: 15,
+		Name:    "drafts and scheduled posts",
+		// status is one of draft, scheduled and published,
+		// publish_at is the time a scheduled post is published at
+		Up: `ALTER TABLE "posts" ADD COLUMN "status" TEXT NOT NULL DEFAULT 'published';
+		ALTER TABLE "posts" ADD COLUMN "publish_at" INTEGER;
+		CREATE INDEX "posts_status_publish_at" ON "posts" ("status", "publish_at");`,
+		Down: `DROP INDEX "posts_status_publish_at";
+		ALTER TABLE "posts" DROP COLUMN "publish_at";
+		ALTER TABLE "posts" DROP COLUMN "status";`,
+	},
+	{
+		Version: 16,
+		Name:    "user join dates",
+		// joined_at of the users registered before it is recorded
+		// is guessed from their first post, NULL if they have none
+		Up: `ALTER TABLE "users" ADD COLUMN "joined_at" INTEGER;
+		UPDATE "users" SET "joined_at" = (SELECT MIN("date_added") FROM "posts" WHERE "sent_by" = "users"."username");
+		CREATE INDEX "posts_sent_by" ON "posts" ("sent_by", "date_added");`,
+		Down: `DROP INDEX "posts_sent_by";
+		ALTER TABLE "users" DROP COLUMN "joined_at";`,
+	},
+	{
+		Version: 17,
+		Name:    "create follows table",
+		// follows table stores which users follow which
+		Up: `CREATE TABLE "follows" (
+			"follower"	TEXT NOT NULL,
+			"followee"	TEXT NOT NULL,
+			"date_added"	INTEGER NOT NULL,
+			PRIMARY KEY("follower", "followee"),
+			FOREIGN KEY("follower") REFERENCES "users"("username"),
+			FOREIGN KEY("followee") REFERENCES "users"("username")
+		);
+		CREATE INDEX "follows_followee" ON "follows" ("followee");`,
+		Down: `DROP TABLE "follows";`,
+	},
+	{
+		Version: 18,
+		Name:    "post change times",
+		// changed_at is the latest time the post or its comments or reactions changed,
+		// deleting and restoring the post and removing reactions included.
+		// It is guessed from the times recorded so far for the existing posts.
+		Up: `ALTER TABLE "posts" ADD COLUMN "changed_at" INTEGER NOT NULL DEFAULT 0;
+		UPDATE "posts" SET "changed_at" = "date_added";
+		UPDATE "posts" SET "changed_at" = "edited_at" WHERE "edited_at" > "changed_at";
+		UPDATE "posts" SET "changed_at" = "deleted_at" WHERE "deleted_at" > "changed_at";
+		UPDATE "posts" SET "changed_at" = (SELECT MAX("date_added") FROM "comments" WHERE "comments"."post_id" = "posts"."id")
+		WHERE (SELECT MAX("date_added") FROM "comments" WHERE "comments"."post_id" = "posts"."id") > "changed_at";
+		UPDATE "posts" SET "changed_at" = (SELECT MAX("edited_at") FROM "comments" WHERE "comments"."post_id" = "posts"."id")
+		WHERE (SELECT MAX("edited_at") FROM "comments" WHERE "comments"."post_id" = "posts"."id") > "changed_at";
+		UPDATE "posts" SET "changed_at" = (SELECT MAX("deleted_at") FROM "comments" WHERE "comments"."post_id" = "posts"."id")
+		WHERE (SELECT MAX("deleted_at") FROM "comments" WHERE "comments"."post_id" = "posts"."id") > "changed_at";
+		UPDATE "posts" SET "changed_at" = (SELECT MAX("date_added") FROM "post_reactions" WHERE "post_reactions"."post_id" = "posts"."id")
+		WHERE (SELECT MAX("date_added") FROM "post_reactions" WHERE "post_reactions"."post_id" = "posts"."id") > "changed_at";`,
+		Down: `ALTER TABLE "posts" DROP COLUMN "changed_at";`,
+	},
+}
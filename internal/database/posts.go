@@ -0,0 +1,125 @@
+package database
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/furkanpala/post-app/internal/core"
+)
+
+// ErrInvalidCursor is returned when a cursor string cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Orders of the posts listing
+const (
+	SortNewest        = "newest"
+	SortOldest        = "oldest"
+	SortMostCommented = "most_commented"
+)
+
+// PostQuery describes which page of the posts listing to return.
+// Posts are ordered newest first unless Sort says otherwise.
+type PostQuery struct {
+	// Author, if not empty, lists only the posts sent by the user
+	Author string
+	// Since, if not zero, lists only the posts dated at or after the unix time
+	Since int64
+	// Until, if not zero, lists only the posts dated before the unix time
+	Until int64
+	// Title, if not empty, lists only the posts whose title contains it, ignoring case
+	Title string
+	// FollowedBy, if not empty, lists only the posts of the users followed by the user
+	FollowedBy string
+	// Tags, if not empty, lists only the posts tagged with all of them
+	Tags []string
+	// Sort is one of SortNewest, SortOldest and SortMostCommented, empty means SortNewest.
+	// Cursors can only be used with SortNewest.
+	Sort string
+	// Limit is the maximum number of posts, 0 means no limit
+	Limit int
+	// Offset is the number of posts to skip
+	Offset int
+	// After, if not nil, lists only the posts that come after the cursor
+	After *Cursor
+	// PublishedAfter, if not nil, lists only the posts which come after the cursor oldest first,
+	// which were published after the post it points at
+	PublishedAfter *Cursor
+}
+
+// ListingState tells when the posts selected by a PostQuery were last changed.
+// It is cheaper to get than the posts and is used to answer conditional requests.
+type ListingState struct {
+	// Count is the number of posts
+	Count int
+	// LatestID is the greatest id among the posts, 0 if there are none
+	LatestID int
+	// LatestDate is the date of the newest post, 0 if there are none
+	LatestDate int64
+	// Comments is the number of comments on the posts, deleted ones included
+	Comments int
+	// Reactions is the number of reactions to the posts
+	Reactions int
+	// LastModified is the latest time a post was added, published, edited, deleted or restored
+	// or a comment or a reaction on the posts was added, changed or removed, 0 if there are no posts
+	LastModified int64
+}
+
+// filters returns q without its paging and sorting fields
+func (q PostQuery) filters() PostQuery {
+	return PostQuery{Author: q.Author, Since: q.Since, Until: q.Until, Title: q.Title, FollowedBy: q.FollowedBy, Tags: q.Tags}
+}
+
+// Cursor is a position in the posts listing.
+// Unlike an offset it does not shift when new posts are added.
+type Cursor struct {
+	Date int64
+	ID   int
+}
+
+// CursorOf returns the cursor pointing at given post
+func CursorOf(post core.Post) Cursor {
+	return Cursor{Date: post.Date, ID: post.ID}
+}
+
+// String encodes the cursor into an opaque URL safe string
+func (c Cursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", c.Date, c.ID)))
+}
+
+// ParseCursor decodes a cursor encoded by Cursor.String
+func ParseCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 2 {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	date, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return Cursor{Date: date, ID: id}, nil
+}
+
+// before reports whether post comes after the cursor in the listing,
+// meaning that it is older than the post cursor points at
+func (c Cursor) before(post core.Post) bool {
+	return post.Date < c.Date || (post.Date == c.Date && post.ID < c.ID)
+}
+
+// after reports whether post comes after the cursor in the oldest first order
+func (c Cursor) after(post core.Post) bool {
+	return post.Date > c.Date || (post.Date == c.Date && post.ID > c.ID)
+}
@@ -0,0 +1,31 @@
+package database
+
+import (
+	"log"
+	"time"
+
+	"github.com/furkanpala/post-app/internal/core"
+)
+
+// RunPublishScheduler publishes the scheduled posts of store whose time has come every interval
+// and passes them to published, oldest first.
+// It blocks until stop is closed, so it is meant to be run on its own goroutine.
+func RunPublishScheduler(store Store, interval time.Duration, published func([]core.Post), stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			posts, err := store.PublishScheduledPosts(now.Unix())
+			if err != nil {
+				log.Printf("Scheduled publishing error: %v\n", err)
+			}
+			if len(posts) > 0 {
+				published(posts)
+			}
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package database
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Markers put around the matched terms by the database.
+// They are replaced with <mark> tags after the text is HTML escaped.
+const (
+	highlightStart = "\ue000"
+	highlightEnd   = "\ue001"
+)
+
+// SearchQuery describes a full-text search on posts
+type SearchQuery struct {
+	// Text is the query as the user typed it.
+	// Words are matched as a whole, "quoted words" as a phrase
+	// and a word ending with * as a prefix.
+	Text string
+	// Author, if not empty, restricts the search to the posts sent by the user
+	Author string
+	// Limit is the maximum number of results, 0 means no limit
+	Limit int
+	// Offset is the number of results to skip
+	Offset int
+}
+
+// searchTerm is a word or a phrase all of whose words must match
+type searchTerm struct {
+	words  []string
+	prefix bool
+}
+
+// parseSearchQuery splits text into terms.
+// Anything other than letters and digits separates words,
+// so the terms are safe to put into a query of any dialect.
+func parseSearchQuery(text string) []searchTerm {
+	var terms []searchTerm
+
+	parts := strings.Split(text, `"`)
+	for i, part := range parts {
+		// Odd parts are between quotes
+		if i%2 == 1 {
+			if words := splitWords(part); len(words) > 0 {
+				terms = append(terms, searchTerm{words: words})
+			}
+			continue
+		}
+
+		for _, field := range strings.Fields(part) {
+			prefix := strings.HasSuffix(field, "*")
+			for _, word := range splitWords(field) {
+				terms = append(terms, searchTerm{words: []string{word}})
+			}
+			if prefix && len(terms) > 0 {
+				terms[len(terms)-1].prefix = true
+			}
+		}
+	}
+
+	return terms
+}
+
+// splitWords splits s into lower cased words of letters and digits
+func splitWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// fts5Match builds the FTS5 MATCH expression of terms
+func fts5Match(terms []searchTerm) string {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		part := `"` + strings.Join(term.words, " ") + `"`
+		if term.prefix {
+			part += "*"
+		}
+		parts = append(parts, part)
+	}
+
+	return strings.Join(parts, " AND ")
+}
+
+// tsQuery builds the PostgreSQL tsquery of terms
+func tsQuery(terms []searchTerm) string {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		words := make([]string, len(term.words))
+		copy(words, term.words)
+		if term.prefix {
+			words[len(words)-1] += ":*"
+		}
+		parts = append(parts, "("+strings.Join(words, " <-> ")+")")
+	}
+
+	return strings.Join(parts, " & ")
+}
+
+// renderHighlight escapes text for HTML and turns the highlight markers into <mark> tags
+func renderHighlight(text string) string {
+	return strings.NewReplacer(
+		highlightStart, "<mark>",
+		highlightEnd, "</mark>",
+	).Replace(html.EscapeString(text))
+}
+
+// highlightTerms puts highlight markers around the words of text which match terms.
+// It is the in-memory counterpart of the highlighting done by the databases.
+func highlightTerms(text string, terms []searchTerm) string {
+	var b strings.Builder
+
+	word := func(start, end int) {
+		w := text[start:end]
+		if matchesWord(strings.ToLower(w), terms) {
+			b.WriteString(highlightStart + w + highlightEnd)
+			return
+		}
+		b.WriteString(w)
+	}
+
+	start := -1
+	for i, r := range text {
+		isWordRune := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWordRune && start < 0 {
+			start = i
+		}
+		if !isWordRune && start >= 0 {
+			word(start, i)
+			start = -1
+		}
+		if !isWordRune {
+			b.WriteRune(r)
+		}
+	}
+	if start >= 0 {
+		word(start, len(text))
+	}
+
+	return b.String()
+}
+
+// matchesWord reports whether the lower cased word is one of the words of terms
+func matchesWord(word string, terms []searchTerm) bool {
+	for _, term := range terms {
+		for i, w := range term.words {
+			if w == word || (term.prefix && i == len(term.words)-1 && strings.HasPrefix(word, w)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchesTerms reports whether text contains every term,
+// phrases as consecutive words
+func matchesTerms(text string, terms []searchTerm) bool {
+	words := splitWords(text)
+
+	for _, term := range terms {
+		if !containsTerm(words, term) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// containsTerm reports whether term occurs in words
+func containsTerm(words []string, term searchTerm) bool {
+	for i := 0; i+len(term.words) <= len(words); i++ {
+		matched := true
+		for j, w := range term.words {
+			last := j == len(term.words)-1
+			if words[i+j] != w && !(last && term.prefix && strings.HasPrefix(words[i+j], w)) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
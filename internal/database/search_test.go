@@ -0,0 +1,129 @@
+package database
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/furkanpala/post-app/internal/core"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		text string
+		want []searchTerm
+	}{
+		{"Go", []searchTerm{{words: []string{"go"}}}},
+		{"go concurrency", []searchTerm{{words: []string{"go"}}, {words: []string{"concurrency"}}}},
+		{`"Go concurrency" patterns`, []searchTerm{{words: []string{"go", "concurrency"}}, {words: []string{"patterns"}}}},
+		{"concur*", []searchTerm{{words: []string{"concur"}, prefix: true}}},
+		{`"go concur"*`, []searchTerm{{words: []string{"go", "concur"}, prefix: true}}},
+		{`go* OR "NEAR(x)" ^col:`, []searchTerm{
+			{words: []string{"go"}, prefix: true},
+			{words: []string{"or"}},
+			{words: []string{"near", "x"}},
+			{words: []string{"col"}},
+		}},
+		{`"" * -`, nil},
+	}
+
+	for _, test := range tests {
+		if got := parseSearchQuery(test.text); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("parseSearchQuery(%q) = %+v, want %+v", test.text, got, test.want)
+		}
+	}
+}
+
+func TestHighlightTerms(t *testing.T) {
+	terms := parseSearchQuery(`"go concurrency" chan*`)
+
+	got := renderHighlight(highlightTerms("<b>Go</b> concurrency & channels", terms))
+	want := "&lt;b&gt;<mark>Go</mark>&lt;/b&gt; <mark>concurrency</mark> &amp; <mark>channels</mark>"
+	if got != want {
+		t.Errorf("highlight = %q, want %q", got, want)
+	}
+}
+
+func TestSearchPosts(t *testing.T) {
+	forEachStore(t, testSearchPosts)
+}
+
+// TestSearchPostsLike runs the search tests on SQLite without its full-text index,
+// like a build without FTS5 does
+func TestSearchPostsLike(t *testing.T) {
+	store, remove := openSQLiteFile(t)
+	defer remove()
+	store.fullText = false
+
+	testSearchPosts(t, store)
+}
+
+func testSearchPosts(t *testing.T, store Store) {
+	concurrency := addPost(t, store, core.Post{
+		Title:   "Go concurrency patterns",
+		Content: "Channels and goroutines make concurrent code simple.",
+		User:    "alice",
+	})
+	pasta := addPost(t, store, core.Post{
+		Title:   "Cooking pasta",
+		Content: "Boil water and add the pasta, concurrency is not needed here.",
+		User:    "bob",
+	})
+	patterns := addPost(t, store, core.Post{
+		Title:   "Patterns of concurrency",
+		Content: "Worker pools and pipelines.",
+		User:    "alice",
+	})
+	deleted := addPost(t, store, core.Post{Title: "Deleted concurrency", Content: "Gone", User: "alice"})
+	if err := store.DeletePost(deleted.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		query  SearchQuery
+		wantID []int
+	}{
+		{"word", SearchQuery{Text: "concurrency"}, []int{concurrency.ID, pasta.ID, patterns.ID}},
+		{"words", SearchQuery{Text: "pasta water"}, []int{pasta.ID}},
+		{"phrase", SearchQuery{Text: `"concurrency patterns"`}, []int{concurrency.ID}},
+		{"prefix", SearchQuery{Text: "gorout*"}, []int{concurrency.ID}},
+		{"author", SearchQuery{Text: "concurrency", Author: "alice"}, []int{concurrency.ID, patterns.ID}},
+		{"no match", SearchQuery{Text: "rust"}, nil},
+		{"no words", SearchQuery{Text: `"*"`}, nil},
+	}
+
+	for _, test := range tests {
+		results, err := store.SearchPosts(test.query)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		var got []int
+		for _, result := range results {
+			got = append(got, result.ID)
+		}
+		sort.Ints(got)
+		if !reflect.DeepEqual(got, test.wantID) {
+			t.Errorf("%s: search %+v found posts %v, want %v", test.name, test.query, got, test.wantID)
+		}
+	}
+
+	markup := addPost(t, store, core.Post{Title: "<b>Bold</b> markup", Content: "<script>alert(1)</script> markup", User: "alice"})
+	results, err := store.SearchPosts(SearchQuery{Text: "markup"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].ID != markup.ID {
+		t.Fatalf("search found %d posts, want the post with markup", len(results))
+	}
+	for _, highlight := range []string{results[0].TitleHighlight, results[0].Snippet} {
+		if !strings.Contains(highlight, "<mark>markup</mark>") {
+			t.Errorf("highlight %q does not mark the matched word", highlight)
+		}
+		if strings.Contains(highlight, "<b>") || strings.Contains(highlight, "<script>") {
+			t.Errorf("highlight %q is not HTML escaped", highlight)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"database/sql"
+
+	"github.com/furkanpala/post-app/internal/core"
+)
+
+// attachmentColumns are the columns of attachments table in the order attachmentFields returns
+const attachmentColumns = "id, content_type, size, width, height, uploaded_by, date_added, COALESCE(post_id, 0)"
+
+// attachmentFields returns pointers to the fields of attachment to scan attachmentColumns into
+func attachmentFields(a *core.Attachment) []interface{} {
+	return []interface{}{&a.ID, &a.ContentType, &a.Size, &a.Width, &a.Height, &a.User, &a.Date, &a.PostID}
+}
+
+// AddAttachment adds an attachment, which is not attached to any post yet, to database
+func (s *SQLStore) AddAttachment(a *core.Attachment) error {
+	_, err := s.exec(`INSERT INTO attachments(id,content_type,size,width,height,uploaded_by,date_added)
+		values(?,?,?,?,?,?,?)`, a.ID, a.ContentType, a.Size, a.Width, a.Height, a.User, a.Date)
+
+	return err
+}
+
+// FindAttachment function returns the attachment with given id, ErrNotFound if there is no such attachment
+func (s *SQLStore) FindAttachment(id string) (*core.Attachment, error) {
+	var attachment core.Attachment
+
+	err := s.queryRow("SELECT "+attachmentColumns+" FROM attachments WHERE id = ?", id).
+		Scan(attachmentFields(&attachment)...)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+// PurgeUnattachedAttachments function removes the attachments uploaded at or before the given unix time
+// which are not attached to any post and returns their ids
+func (s *SQLStore) PurgeUnattachedAttachments(before int64) ([]string, error) {
+	var ids []string
+
+	err := inTx(s.db, func(tx *sql.Tx) error {
+		rows, err := tx.Query(s.dialect.rebind(`SELECT id FROM attachments
+			WHERE post_id IS NULL AND date_added <= ?`), before)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		rows.Close()
+
+		_, err = tx.Exec(s.dialect.rebind("DELETE FROM attachments WHERE post_id IS NULL AND date_added <= ?"), before)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// loadRelations fills in the fields of the posts kept in other tables than posts
+func (s *SQLStore) loadRelations(posts []core.Post) error {
+	if err := s.loadTags(posts); err != nil {
+		return err
+	}
+
+	return s.loadAttachments(posts)
+}
+
+// loadAttachments fills in the attachment ids of the posts, in upload order
+func (s *SQLStore) loadAttachments(posts []core.Post) error {
+	return inPostBatches(posts, func(batch []core.Post, in string, ids []interface{}) error {
+		index := make(map[int]int, len(batch))
+		for i, post := range batch {
+			index[post.ID] = i
+		}
+
+		rows, err := s.query(`SELECT post_id, id FROM attachments
+			WHERE post_id IN `+in+` ORDER BY post_id, date_added, id`, ids...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var postID int
+			var id string
+			if err := rows.Scan(&postID, &id); err != nil {
+				return err
+			}
+
+			i := index[postID]
+			batch[i].AttachmentIDs = append(batch[i].AttachmentIDs, id)
+		}
+
+		return rows.Err()
+	})
+}
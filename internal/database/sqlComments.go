@@ -0,0 +1,181 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/furkanpala/post-app/internal/core"
+)
+
+// commentColumns are the columns of comments table, aliased as c, in the order commentFields returns
+const commentColumns = "c.id, c.post_id, COALESCE(c.parent_id, 0), c.content, c.sent_by, c.date_added, " +
+	"COALESCE(c.edited_at, 0), COALESCE(c.deleted_at, 0)"
+
+// commentFields returns pointers to the fields of comment to scan commentColumns into
+func commentFields(comment *core.Comment) []interface{} {
+	return []interface{}{&comment.ID, &comment.PostID, &comment.ParentID, &comment.Content, &comment.User,
+		&comment.Date, &comment.EditedAt, &comment.DeletedAt}
+}
+
+// ListComments function returns the top level comments selected by q
+// followed by all of their replies, both ordered oldest first
+func (s *SQLStore) ListComments(q CommentQuery) ([]core.Comment, error) {
+	query := "SELECT " + commentColumns + " FROM comments c WHERE c.post_id = ? AND c.parent_id IS NULL ORDER BY c.id"
+	args := []interface{}{q.PostID}
+
+	if q.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, q.Limit, q.Offset)
+	}
+
+	comments, err := s.queryComments(query, args...)
+	if err != nil || len(comments) == 0 {
+		return comments, err
+	}
+
+	threadIDs := make([]interface{}, len(comments))
+	for i, comment := range comments {
+		threadIDs[i] = comment.ID
+	}
+
+	replies, err := s.queryComments("SELECT "+commentColumns+" FROM comments c WHERE c.thread_id IN (?"+
+		strings.Repeat(",?", len(threadIDs)-1)+") ORDER BY c.id", threadIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(comments, replies...), nil
+}
+
+// queryComments runs query which selects commentColumns
+func (s *SQLStore) queryComments(query string, args ...interface{}) ([]core.Comment, error) {
+	var comments []core.Comment
+
+	rows, err := s.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var comment core.Comment
+		if err := rows.Scan(commentFields(&comment)...); err != nil {
+			return comments, err
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, rows.Err()
+}
+
+// CountComments function returns the number of top level comments of the post
+func (s *SQLStore) CountComments(postID int) (int, error) {
+	var count int
+	err := s.queryRow("SELECT COUNT(*) FROM comments WHERE post_id = ? AND parent_id IS NULL", postID).Scan(&count)
+
+	return count, err
+}
+
+// FindComment function returns the comment with given id, ErrNotFound if there is no such comment
+func (s *SQLStore) FindComment(id int) (*core.Comment, error) {
+	var comment core.Comment
+
+	err := s.queryRow("SELECT "+commentColumns+" FROM comments c WHERE c.id = ?", id).Scan(commentFields(&comment)...)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// AddComment function adds the comment into database and sets its id and date.
+// Replies are put into the thread of the comment they reply to.
+func (s *SQLStore) AddComment(comment *core.Comment) error {
+	var parentID, threadID interface{}
+
+	if comment.ParentID != 0 {
+		// Thread of a top level comment is itself
+		var parentThreadID int64
+		err := s.queryRow("SELECT COALESCE(thread_id, id) FROM comments WHERE id = ?", comment.ParentID).
+			Scan(&parentThreadID)
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		parentID = comment.ParentID
+		threadID = parentThreadID
+	}
+
+	date := time.Now().Unix()
+	var id int64
+	err := inTx(s.db, func(tx *sql.Tx) error {
+		var err error
+		id, err = s.dialect.insert(tx,
+			"INSERT INTO comments(post_id,parent_id,thread_id,content,sent_by,date_added) values(?,?,?,?,?,?)",
+			comment.PostID, parentID, threadID, comment.Content, comment.User, date)
+		if err != nil {
+			return err
+		}
+
+		return s.touchPost(tx, comment.PostID, date)
+	})
+	if err != nil {
+		return err
+	}
+
+	comment.ID = int(id)
+	comment.Date = date
+	return nil
+}
+
+// UpdateComment function saves the content of the comment if it is not deleted
+func (s *SQLStore) UpdateComment(comment *core.Comment) error {
+	editedAt := time.Now().Unix()
+
+	err := s.changeComment(comment.ID, editedAt, "UPDATE comments SET content = ?, edited_at = ? WHERE id = ? AND deleted_at IS NULL",
+		comment.Content, editedAt, comment.ID)
+	if err != nil {
+		return err
+	}
+
+	comment.EditedAt = editedAt
+	return nil
+}
+
+// DeleteComment function clears the content of the comment and marks it deleted.
+// The comment stays in database so that its replies are still in the thread.
+func (s *SQLStore) DeleteComment(id int) error {
+	now := time.Now().Unix()
+	return s.changeComment(id, now, "UPDATE comments SET content = '', deleted_at = ? WHERE id = ? AND deleted_at IS NULL",
+		now, id)
+}
+
+// changeComment runs the UPDATE statement query on the comment with given id
+// and records now as the time its post changed, returns ErrNotFound if no comment is updated
+func (s *SQLStore) changeComment(id int, now int64, query string, args ...interface{}) error {
+	return inTx(s.db, func(tx *sql.Tx) error {
+		result, err := tx.Exec(s.dialect.rebind(query), args...)
+		if err != nil {
+			return err
+		}
+
+		updated, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if updated == 0 {
+			return ErrNotFound
+		}
+
+		_, err = tx.Exec(s.dialect.rebind("UPDATE posts SET changed_at = ? WHERE id = (SELECT post_id FROM comments WHERE id = ?)"),
+			now, id)
+		return err
+	})
+}
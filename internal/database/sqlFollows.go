@@ -0,0 +1,65 @@
+package database
+
+import "time"
+
+// Follow function makes follower follow followee, does nothing if it already does
+func (s *SQLStore) Follow(follower, followee string) error {
+	_, err := s.exec("INSERT INTO follows(follower,followee,date_added) values(?,?,?) ON CONFLICT DO NOTHING",
+		follower, followee, time.Now().Unix())
+
+	return err
+}
+
+// Unfollow function makes follower stop following followee
+func (s *SQLStore) Unfollow(follower, followee string) error {
+	_, err := s.exec("DELETE FROM follows WHERE follower = ? AND followee = ?", follower, followee)
+
+	return err
+}
+
+// ListFollowers function returns the users following the user, most recent first
+func (s *SQLStore) ListFollowers(username string) ([]string, error) {
+	return s.queryUsernames("SELECT follower FROM follows WHERE followee = ? ORDER BY date_added DESC, follower", username)
+}
+
+// ListFollowing function returns the users followed by the user, most recent first
+func (s *SQLStore) ListFollowing(username string) ([]string, error) {
+	return s.queryUsernames("SELECT followee FROM follows WHERE follower = ? ORDER BY date_added DESC, followee", username)
+}
+
+// CountFollowers function returns the number of users following the user
+func (s *SQLStore) CountFollowers(username string) (int, error) {
+	var count int
+	err := s.queryRow("SELECT COUNT(*) FROM follows WHERE followee = ?", username).Scan(&count)
+
+	return count, err
+}
+
+// CountFollowing function returns the number of users followed by the user
+func (s *SQLStore) CountFollowing(username string) (int, error) {
+	var count int
+	err := s.queryRow("SELECT COUNT(*) FROM follows WHERE follower = ?", username).Scan(&count)
+
+	return count, err
+}
+
+// queryUsernames runs query which selects a single column of usernames
+func (s *SQLStore) queryUsernames(query string, args ...interface{}) ([]string, error) {
+	var usernames []string
+
+	rows, err := s.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var username string
+		if err := rows.Scan(&username); err != nil {
+			return usernames, err
+		}
+		usernames = append(usernames, username)
+	}
+
+	return usernames, rows.Err()
+}
@@ -0,0 +1,767 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/furkanpala/post-app/internal/core"
+	"github.com/furkanpala/post-app/internal/markdown"
+)
+
+// postColumns are the columns of posts table, aliased as p, in the order postFields returns
+const postColumns = "p.id, p.title, p.content, COALESCE(p.content_html, ''), p.sent_by, p.date_added, COALESCE(p.edited_at, 0), p.version, " +
+	"COALESCE(p.deleted_at, 0), p.status, COALESCE(p.publish_at, 0), " + commentCount
+
+// commentCount counts the comments of the post aliased as p which are not deleted
+const commentCount = "(SELECT COUNT(*) FROM comments c WHERE c.post_id = p.id AND c.deleted_at IS NULL)"
+
+// postFields returns pointers to the fields of post to scan postColumns into
+func postFields(post *core.Post) []interface{} {
+	return []interface{}{&post.ID, &post.Title, &post.Content, &post.ContentHTML, &post.User, &post.Date, &post.EditedAt, &post.Version,
+		&post.DeletedAt, &post.Status, &post.PublishAt, &post.CommentCount}
+}
+
+// visiblePost is the condition of the posts, aliased as p, which are listed publicly
+const visiblePost = "p.deleted_at IS NULL AND p.status = '" + core.PostPublished + "'"
+
+// CountPosts function returns the number of posts in database selected by q.
+// Paging fields of q are ignored.
+func (s *SQLStore) CountPosts(q PostQuery) (int, error) {
+	conditions, args := postConditions(q.filters())
+
+	var count int
+	err := s.queryRow("SELECT COUNT(*) FROM posts p WHERE "+conditions, args...).Scan(&count)
+
+	return count, err
+}
+
+// ListingState returns the count, the latest id and date of the posts in database selected by q,
+// the counts of their comments and reactions and the latest time they changed.
+// Paging fields of q are ignored.
+func (s *SQLStore) ListingState(q PostQuery) (ListingState, error) {
+	conditions, args := postConditions(q.filters())
+
+	var state ListingState
+	err := s.queryRow("SELECT COUNT(*), COALESCE(MAX(p.id), 0), COALESCE(MAX(p.date_added), 0) FROM posts p WHERE "+conditions,
+		args...).Scan(&state.Count, &state.LatestID, &state.LatestDate)
+	if err != nil {
+		return state, err
+	}
+
+	err = s.queryRow("SELECT COUNT(*) FROM comments c JOIN posts p ON p.id = c.post_id WHERE "+conditions,
+		args...).Scan(&state.Comments)
+	if err != nil {
+		return state, err
+	}
+
+	err = s.queryRow("SELECT COUNT(*) FROM post_reactions r JOIN posts p ON p.id = r.post_id WHERE "+conditions,
+		args...).Scan(&state.Reactions)
+	if err != nil {
+		return state, err
+	}
+
+	// Posts which are deleted or not published are also considered,
+	// as deleting them or taking them out of the listing is a change too
+	query := "SELECT COALESCE(MAX(p.changed_at), 0) FROM posts p"
+	filters, args := filterConditions(q.filters())
+	if len(filters) > 0 {
+		query += " WHERE " + strings.Join(filters, " AND ")
+	}
+	err = s.queryRow(query, args...).Scan(&state.LastModified)
+
+	return state, err
+}
+
+// postConditions returns the WHERE conditions on posts, aliased as p, selecting the posts of q
+func postConditions(q PostQuery) (string, []interface{}) {
+	conditions, args := filterConditions(q)
+
+	return strings.Join(append([]string{visiblePost}, conditions...), " AND "), args
+}
+
+// filterConditions returns the WHERE conditions on posts, aliased as p, selecting the posts of q
+// whether or not they are deleted or published
+func filterConditions(q PostQuery) ([]string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if q.Author != "" {
+		conditions = append(conditions, "p.sent_by = ?")
+		args = append(args, q.Author)
+	}
+
+	if q.Since != 0 {
+		conditions = append(conditions, "p.date_added >= ?")
+		args = append(args, q.Since)
+	}
+
+	if q.Until != 0 {
+		conditions = append(conditions, "p.date_added < ?")
+		args = append(args, q.Until)
+	}
+
+	if q.Title != "" {
+		conditions = append(conditions, `LOWER(p.title) LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+likeEscaper.Replace(strings.ToLower(q.Title))+"%")
+	}
+
+	if q.FollowedBy != "" {
+		conditions = append(conditions, "p.sent_by IN (SELECT followee FROM follows WHERE follower = ?)")
+		args = append(args, q.FollowedBy)
+	}
+
+	if len(q.Tags) > 0 {
+		conditions = append(conditions, `p.id IN (SELECT pt.post_id FROM post_tags pt JOIN tags t ON t.id = pt.tag_id
+			WHERE t.name IN (?`+strings.Repeat(",?", len(q.Tags)-1)+`) GROUP BY pt.post_id HAVING COUNT(*) = ?)`)
+		for _, tag := range q.Tags {
+			args = append(args, tag)
+		}
+		args = append(args, len(q.Tags))
+	}
+
+	if q.After != nil {
+		conditions = append(conditions, "(p.date_added < ? OR (p.date_added = ? AND p.id < ?))")
+		args = append(args, q.After.Date, q.After.Date, q.After.ID)
+	}
+
+	if q.PublishedAfter != nil {
+		conditions = append(conditions, "(p.date_added > ? OR (p.date_added = ? AND p.id > ?))")
+		args = append(args, q.PublishedAfter.Date, q.PublishedAfter.Date, q.PublishedAfter.ID)
+	}
+
+	return conditions, args
+}
+
+// touchPost records now as the time the post with given id, its comments or its reactions last changed
+func (s *SQLStore) touchPost(q querier, id int, now int64) error {
+	_, err := q.Exec(s.dialect.rebind("UPDATE posts SET changed_at = ? WHERE id = ?"), now, id)
+	return err
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// postOrder returns the ORDER BY clause of the sort order, newest first by default
+func postOrder(sort string) string {
+	switch sort {
+	case SortOldest:
+		return " ORDER BY p.date_added ASC, p.id ASC"
+	case SortMostCommented:
+		return " ORDER BY " + commentCount + " DESC, p.date_added DESC, p.id DESC"
+	default:
+		return " ORDER BY p.date_added DESC, p.id DESC"
+	}
+}
+
+// GetAllPosts returns all the posts inside database
+func (s *SQLStore) GetAllPosts() ([]core.Post, error) {
+	return s.ListPosts(PostQuery{})
+}
+
+// ListPosts returns the posts selected by q.
+// Paging is done by the database with LIMIT/OFFSET
+// or with a keyset condition when q has a cursor.
+func (s *SQLStore) ListPosts(q PostQuery) ([]core.Post, error) {
+	var posts []core.Post
+
+	conditions, args := postConditions(q)
+
+	query := "SELECT " + postColumns + " FROM posts p WHERE " + conditions
+	query += postOrder(q.Sort)
+
+	if q.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, q.Limit, q.Offset)
+	}
+
+	rows, err := s.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var post core.Post
+		if err := rows.Scan(postFields(&post)...); err != nil {
+			return posts, err
+		}
+		posts = append(posts, post)
+	}
+	if err := rows.Err(); err != nil {
+		return posts, err
+	}
+
+	return posts, s.loadRelations(posts)
+}
+
+// SearchPosts runs a full-text search on title and content of posts.
+// SQLite uses posts_fts table, PostgreSQL uses search_vector column of posts.
+// SQLite built without FTS5 falls back to searchPostsLike.
+func (s *SQLStore) SearchPosts(q SearchQuery) ([]core.SearchResult, error) {
+	terms := parseSearchQuery(q.Text)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	if !s.fullText {
+		return s.searchPostsLike(q, terms)
+	}
+
+	var query string
+	var args []interface{}
+
+	if s.dialect.name == postgresDialect.name {
+		options := "StartSel=" + highlightStart + ", StopSel=" + highlightEnd
+		query = `SELECT ` + postColumns + `,
+			ts_headline('english', p.title, q, ?),
+			ts_headline('english', p.content, q, ?)
+			FROM posts p, to_tsquery('english', ?) q
+			WHERE p.search_vector @@ q AND ` + visiblePost
+		args = append(args, options+", HighlightAll=true", options+", MaxWords=35, MinWords=15", tsQuery(terms))
+	} else {
+		query = `SELECT ` + postColumns + `,
+			highlight(posts_fts, 0, ?, ?),
+			snippet(posts_fts, 1, ?, ?, '...', 24)
+			FROM posts_fts JOIN posts p ON p.id = posts_fts.rowid
+			WHERE posts_fts MATCH ? AND ` + visiblePost
+		args = append(args, highlightStart, highlightEnd, highlightStart, highlightEnd, fts5Match(terms))
+	}
+
+	if q.Author != "" {
+		query += " AND p.sent_by = ?"
+		args = append(args, q.Author)
+	}
+
+	if s.dialect.name == postgresDialect.name {
+		query += " ORDER BY ts_rank(p.search_vector, q) DESC, p.id DESC"
+	} else {
+		// Matches in title weigh more than the ones in content
+		query += " ORDER BY bm25(posts_fts, 10.0, 1.0), p.id DESC"
+	}
+
+	if q.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, q.Limit, q.Offset)
+	}
+
+	rows, err := s.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []core.SearchResult
+
+	for rows.Next() {
+		var result core.SearchResult
+		if err := rows.Scan(append(postFields(&result.Post), &result.TitleHighlight, &result.Snippet)...); err != nil {
+			return results, err
+		}
+		result.TitleHighlight = renderHighlight(result.TitleHighlight)
+		result.Snippet = renderHighlight(result.Snippet)
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return results, err
+	}
+
+	posts := make([]core.Post, len(results))
+	for i := range results {
+		posts[i] = results[i].Post
+	}
+	if err := s.loadRelations(posts); err != nil {
+		return results, err
+	}
+	for i := range results {
+		results[i].Post = posts[i]
+	}
+
+	return results, nil
+}
+
+// searchPostsLike searches posts without a full-text index.
+// The posts containing every word of terms are found with LIKE and
+// matched against terms in Go like MemoryStore does. Results are not ranked, newest come first.
+func (s *SQLStore) searchPostsLike(q SearchQuery, terms []searchTerm) ([]core.SearchResult, error) {
+	conditions := []string{visiblePost}
+	var args []interface{}
+
+	for _, term := range terms {
+		for _, word := range term.words {
+			conditions = append(conditions, `(LOWER(p.title) LIKE ? ESCAPE '\' OR LOWER(p.content) LIKE ? ESCAPE '\')`)
+			pattern := "%" + likeEscaper.Replace(word) + "%"
+			args = append(args, pattern, pattern)
+		}
+	}
+
+	if q.Author != "" {
+		conditions = append(conditions, "p.sent_by = ?")
+		args = append(args, q.Author)
+	}
+
+	rows, err := s.query("SELECT "+postColumns+" FROM posts p WHERE "+strings.Join(conditions, " AND ")+
+		" ORDER BY p.date_added DESC, p.id DESC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []core.Post
+	for rows.Next() {
+		var post core.Post
+		if err := rows.Scan(postFields(&post)...); err != nil {
+			return nil, err
+		}
+		if matchesTerms(post.Title+"\n"+post.Content, terms) {
+			posts = append(posts, post)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if q.Offset >= len(posts) {
+		return nil, nil
+	}
+	posts = posts[q.Offset:]
+	if q.Limit > 0 && q.Limit < len(posts) {
+		posts = posts[:q.Limit]
+	}
+
+	if err := s.loadRelations(posts); err != nil {
+		return nil, err
+	}
+
+	results := make([]core.SearchResult, len(posts))
+	for i, post := range posts {
+		results[i] = core.SearchResult{
+			Post:           post,
+			TitleHighlight: renderHighlight(highlightTerms(post.Title, terms)),
+			Snippet:        renderHighlight(highlightTerms(post.Content, terms)),
+		}
+	}
+
+	return results, nil
+}
+
+// AddPost adds a post together with its tags and rendered content to database
+// and attaches the attachments of the post to it
+func (s *SQLStore) AddPost(post *core.Post) error {
+	if post.Status == "" {
+		post.Status = core.PostPublished
+	}
+
+	contentHTML, err := markdown.Render(post.Content)
+	if err != nil {
+		return err
+	}
+
+	var id int64
+	date := time.Now().Unix()
+
+	err = inTx(s.db, func(tx *sql.Tx) error {
+		var err error
+		id, err = s.dialect.insert(tx, `INSERT INTO posts(title,content,content_html,sent_by,date_added,status,publish_at,changed_at)
+			values(?,?,?,?,?,?,?,?)`, post.Title, post.Content, contentHTML, post.User, date,
+			post.Status, nullTime(post.PublishAt), date)
+		if err != nil {
+			return err
+		}
+
+		for _, tag := range post.Tags {
+			_, err := tx.Exec(s.dialect.rebind("INSERT INTO tags(name) values(?) ON CONFLICT DO NOTHING"), tag)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.Exec(s.dialect.rebind(`INSERT INTO post_tags(post_id,tag_id)
+				SELECT ?, id FROM tags WHERE name = ?`), id, tag)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, attachmentID := range post.AttachmentIDs {
+			result, err := tx.Exec(s.dialect.rebind(`UPDATE attachments SET post_id = ?
+				WHERE id = ? AND uploaded_by = ? AND post_id IS NULL`), id, attachmentID, post.User)
+			if err != nil {
+				return err
+			}
+
+			attached, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if attached == 0 {
+				return ErrInvalidAttachment
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	post.ID = int(id)
+	post.ContentHTML = contentHTML
+	post.Date = date
+	post.Version = 1
+	return nil
+}
+
+// FindPost function returns the post with given id, ErrNotFound if there is no such post
+func (s *SQLStore) FindPost(id int) (*core.Post, error) {
+	var post core.Post
+
+	err := s.queryRow("SELECT "+postColumns+" FROM posts p WHERE p.id = ?", id).Scan(postFields(&post)...)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	posts := []core.Post{post}
+	if err := s.loadRelations(posts); err != nil {
+		return nil, err
+	}
+
+	return &posts[0], nil
+}
+
+// UpdatePost function saves title, content and status of post if its version is still version.
+// The replaced title and content are kept as a revision of the post.
+// On success edit time, version and rendered content of post are updated.
+func (s *SQLStore) UpdatePost(post *core.Post, version int) error {
+	editedAt := time.Now().Unix()
+
+	contentHTML, err := markdown.Render(post.Content)
+	if err != nil {
+		return err
+	}
+
+	var date int64
+
+	err = inTx(s.db, func(tx *sql.Tx) error {
+		var revision core.Revision
+		var status string
+		err := tx.QueryRow(s.dialect.rebind(`SELECT title, content, COALESCE(edited_at, date_added), date_added, status
+			FROM posts WHERE id = ? AND version = ? AND deleted_at IS NULL`), post.ID, version).
+			Scan(&revision.Title, &revision.Content, &revision.Date, &date, &status)
+		if err != nil {
+			return err
+		}
+
+		// Date of a post becomes the publishing time when it is published
+		if status != core.PostPublished && post.Status == core.PostPublished {
+			date = editedAt
+		}
+
+		result, err := tx.Exec(s.dialect.rebind(`UPDATE posts SET title = ?, content = ?, content_html = ?, edited_at = ?,
+			date_added = ?, status = ?, publish_at = ?, changed_at = ?, version = version + 1 WHERE id = ? AND version = ?`),
+			post.Title, post.Content, contentHTML, editedAt, date, post.Status, nullTime(post.PublishAt), editedAt, post.ID, version)
+		if err != nil {
+			return err
+		}
+
+		// Someone else may have updated the post after it was selected above
+		updated, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if updated == 0 {
+			return sql.ErrNoRows
+		}
+
+		_, err = tx.Exec(s.dialect.rebind("INSERT INTO post_revisions(post_id,revision,title,content,date_added) values(?,?,?,?,?)"),
+			post.ID, version, revision.Title, revision.Content, revision.Date)
+		return err
+	})
+
+	if err == sql.ErrNoRows {
+		// Either there is no such post or someone else has updated or deleted it
+		stored, err := s.FindPost(post.ID)
+		if err != nil {
+			return err
+		}
+		if stored.DeletedAt != 0 {
+			return ErrNotFound
+		}
+		return ErrVersionConflict
+	}
+	if err != nil {
+		return err
+	}
+
+	post.ContentHTML = contentHTML
+	post.Date = date
+	post.EditedAt = editedAt
+	post.Version = version + 1
+	return nil
+}
+
+// DeletePost function moves the post into the trash of its sender
+func (s *SQLStore) DeletePost(id int) error {
+	return s.setDeletedAt(id, time.Now().Unix(), "deleted_at IS NULL")
+}
+
+// RestorePost function takes the post out of the trash
+func (s *SQLStore) RestorePost(id int) error {
+	return s.setDeletedAt(id, nil, "deleted_at IS NOT NULL")
+}
+
+// setDeletedAt sets deleted_at of the post with given id if the post meets condition
+func (s *SQLStore) setDeletedAt(id int, deletedAt interface{}, condition string) error {
+	result, err := s.exec("UPDATE posts SET deleted_at = ?, changed_at = ? WHERE id = ? AND "+condition,
+		deletedAt, time.Now().Unix(), id)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// ListTrash function returns the deleted posts of the user, most recently deleted first
+func (s *SQLStore) ListTrash(username string) ([]core.Post, error) {
+	var posts []core.Post
+
+	rows, err := s.query("SELECT "+postColumns+" FROM posts p WHERE p.sent_by = ? AND p.deleted_at IS NOT NULL"+
+		" ORDER BY p.deleted_at DESC, p.id DESC", username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var post core.Post
+		if err := rows.Scan(postFields(&post)...); err != nil {
+			return posts, err
+		}
+		posts = append(posts, post)
+	}
+	if err := rows.Err(); err != nil {
+		return posts, err
+	}
+
+	return posts, s.loadRelations(posts)
+}
+
+// PurgeDeletedPosts function removes the posts deleted at or before the given unix time
+// together with their revisions, comments, reactions, tags and attachments for good.
+// Returns the ids of the removed attachments.
+func (s *SQLStore) PurgeDeletedPosts(before int64) (int64, []string, error) {
+	var purged int64
+	var attachmentIDs []string
+
+	err := inTx(s.db, func(tx *sql.Tx) error {
+		rows, err := tx.Query(s.dialect.rebind(`SELECT id FROM attachments WHERE post_id IN
+			(SELECT id FROM posts WHERE deleted_at <= ?)`), before)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			attachmentIDs = append(attachmentIDs, id)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		rows.Close()
+
+		for _, table := range []string{"post_revisions", "comments", "post_reactions", "post_tags", "attachments"} {
+			_, err := tx.Exec(s.dialect.rebind("DELETE FROM "+table+` WHERE post_id IN
+				(SELECT id FROM posts WHERE deleted_at <= ?)`), before)
+			if err != nil {
+				return err
+			}
+		}
+
+		result, err := tx.Exec(s.dialect.rebind("DELETE FROM posts WHERE deleted_at <= ?"), before)
+		if err != nil {
+			return err
+		}
+
+		purged, err = result.RowsAffected()
+		return err
+	})
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return purged, attachmentIDs, nil
+}
+
+// ListRevisions function returns the previous versions of the post, oldest first
+func (s *SQLStore) ListRevisions(postID int) ([]core.Revision, error) {
+	var revisions []core.Revision
+
+	rows, err := s.query(`SELECT post_id, revision, title, content, date_added FROM post_revisions
+		WHERE post_id = ? ORDER BY revision`, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var revision core.Revision
+		if err := rows.Scan(&revision.PostID, &revision.Number, &revision.Title, &revision.Content,
+			&revision.Date); err != nil {
+			return revisions, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, rows.Err()
+}
+
+// FindRevision function returns the nth version of the post, ErrNotFound if there is no such revision
+func (s *SQLStore) FindRevision(postID, n int) (*core.Revision, error) {
+	var revision core.Revision
+
+	err := s.queryRow(`SELECT post_id, revision, title, content, date_added FROM post_revisions
+		WHERE post_id = ? AND revision = ?`, postID, n).
+		Scan(&revision.PostID, &revision.Number, &revision.Title, &revision.Content, &revision.Date)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &revision, nil
+}
+
+// renderContent renders the content of the posts whose rendered content is not in database,
+// which are the ones added before content was rendered
+func (s *SQLStore) renderContent() error {
+	rows, err := s.query("SELECT id, content FROM posts WHERE content_html IS NULL")
+	if err != nil {
+		return err
+	}
+
+	var posts []core.Post
+	for rows.Next() {
+		var post core.Post
+		if err := rows.Scan(&post.ID, &post.Content); err != nil {
+			rows.Close()
+			return err
+		}
+		posts = append(posts, post)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, post := range posts {
+		contentHTML, err := markdown.Render(post.Content)
+		if err != nil {
+			return err
+		}
+		if _, err := s.exec("UPDATE posts SET content_html = ? WHERE id = ?", contentHTML, post.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListDrafts function returns the drafts and scheduled posts of the user which are not deleted,
+// most recently created first
+func (s *SQLStore) ListDrafts(username string) ([]core.Post, error) {
+	var posts []core.Post
+
+	rows, err := s.query("SELECT "+postColumns+" FROM posts p WHERE p.sent_by = ? AND p.deleted_at IS NULL"+
+		" AND p.status <> ? ORDER BY p.id DESC", username, core.PostPublished)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var post core.Post
+		if err := rows.Scan(postFields(&post)...); err != nil {
+			return posts, err
+		}
+		posts = append(posts, post)
+	}
+	if err := rows.Err(); err != nil {
+		return posts, err
+	}
+
+	return posts, s.loadRelations(posts)
+}
+
+// PublishScheduledPosts function publishes the scheduled posts which are not deleted
+// and whose publishing time is at or before now, a unix time, and returns them.
+// Date of a published post becomes now, so they come in the order of the post listings.
+func (s *SQLStore) PublishScheduledPosts(now int64) ([]core.Post, error) {
+	var ids []int
+
+	err := inTx(s.db, func(tx *sql.Tx) error {
+		rows, err := tx.Query(s.dialect.rebind(`SELECT id FROM posts
+			WHERE status = ? AND publish_at <= ? AND deleted_at IS NULL ORDER BY id`),
+			core.PostScheduled, now)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var id int
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		rows.Close()
+
+		for _, id := range ids {
+			_, err := tx.Exec(s.dialect.rebind(`UPDATE posts SET status = ?, date_added = ?, publish_at = NULL, changed_at = ?
+				WHERE id = ?`), core.PostPublished, now, now, id)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	posts := make([]core.Post, 0, len(ids))
+	for _, id := range ids {
+		post, err := s.FindPost(id)
+		if err != nil {
+			return posts, err
+		}
+		posts = append(posts, *post)
+	}
+
+	return posts, nil
+}
+
+// nullTime returns the unix time t as a column value, NULL if it is 0
+func nullTime(t int64) interface{} {
+	if t == 0 {
+		return nil
+	}
+
+	return t
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/furkanpala/post-app/internal/core"
+)
+
+// AddReaction adds the reaction of the user to the post, does nothing if it is already added
+func (s *SQLStore) AddReaction(postID int, username, reaction string) error {
+	now := time.Now().Unix()
+	return s.changeReactions(postID, now, `INSERT INTO post_reactions(post_id,username,reaction,date_added) values(?,?,?,?)
+		ON CONFLICT DO NOTHING`, postID, username, reaction, now)
+}
+
+// RemoveReaction removes the reaction of the user from the post
+func (s *SQLStore) RemoveReaction(postID int, username, reaction string) error {
+	return s.changeReactions(postID, time.Now().Unix(), "DELETE FROM post_reactions WHERE post_id = ? AND username = ? AND reaction = ?",
+		postID, username, reaction)
+}
+
+// changeReactions runs the statement query on the reactions of the post with given id
+// and records now as the time the post changed if any reaction is added or removed
+func (s *SQLStore) changeReactions(postID int, now int64, query string, args ...interface{}) error {
+	return inTx(s.db, func(tx *sql.Tx) error {
+		result, err := tx.Exec(s.dialect.rebind(query), args...)
+		if err != nil {
+			return err
+		}
+
+		changed, err := result.RowsAffected()
+		if err != nil || changed == 0 {
+			return err
+		}
+
+		return s.touchPost(tx, postID, now)
+	})
+}
+
+// LoadReactions fills in the reaction counts of the posts and the reactions the user gave to them
+// with a query grouped by post and reaction for every batch of posts
+func (s *SQLStore) LoadReactions(posts []core.Post, username string) error {
+	return inPostBatches(posts, func(batch []core.Post, in string, ids []interface{}) error {
+		index := make(map[int]int, len(batch))
+		for i, post := range batch {
+			index[post.ID] = i
+		}
+
+		rows, err := s.query(`SELECT post_id, reaction, COUNT(*), SUM(CASE WHEN username = ? THEN 1 ELSE 0 END)
+			FROM post_reactions WHERE post_id IN `+in+`
+			GROUP BY post_id, reaction ORDER BY post_id, reaction`, append([]interface{}{username}, ids...)...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var postID, count, reacted int
+			var reaction string
+			if err := rows.Scan(&postID, &reaction, &count, &reacted); err != nil {
+				return err
+			}
+
+			post := &batch[index[postID]]
+			if post.Reactions == nil {
+				post.Reactions = make(map[string]int)
+			}
+			post.Reactions[reaction] = count
+			if reacted > 0 {
+				post.Reacted = append(post.Reacted, reaction)
+			}
+		}
+
+		return rows.Err()
+	})
+}
@@ -0,0 +1,239 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/furkanpala/post-app/internal/core"
+	_ "github.com/lib/pq"           // PostgreSQL driver
+	_ "github.com/mattn/go-sqlite3" // SQLite driver
+)
+
+var (
+	_ Store    = (*SQLStore)(nil)
+	_ Migrator = (*SQLStore)(nil)
+)
+
+// SQLStore is a Store backed by an SQL database, either SQLite or PostgreSQL
+type SQLStore struct {
+	db      *sql.DB
+	dialect dialect
+	// fullText tells whether posts are indexed for full-text search,
+	// it is known once the store is migrated
+	fullText bool
+}
+
+// Open function opens the database described by dsn.
+// DSNs starting with postgres:// or postgresql:// open a PostgreSQL database,
+// anything else is taken as the path of an SQLite database file.
+func Open(dsn string) (*SQLStore, error) {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		return OpenPostgres(dsn)
+	}
+
+	return OpenSQLite(strings.TrimPrefix(dsn, "sqlite://"))
+}
+
+// OpenSQLite function opens the SQLite database file at given path
+func OpenSQLite(path string) (*SQLStore, error) {
+	return open(sqliteDialect, path)
+}
+
+// OpenPostgres function opens the PostgreSQL database at given dsn
+func OpenPostgres(dsn string) (*SQLStore, error) {
+	return open(postgresDialect, dsn)
+}
+
+func open(d dialect, dsn string) (*SQLStore, error) {
+	db, err := sql.Open(d.driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLStore{db: db, dialect: d}, nil
+}
+
+// Close closes the underlying database
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
+
+// MigrateUp applies every pending migration
+// and renders the content of the posts added before content was rendered
+func (s *SQLStore) MigrateUp() error {
+	if err := migrateUp(s.db, s.dialect, migrations); err != nil {
+		return err
+	}
+
+	if err := s.detectFullText(); err != nil {
+		return err
+	}
+
+	return s.renderContent()
+}
+
+// detectFullText finds out whether posts are indexed for full-text search.
+// PostgreSQL always indexes them, SQLite only if it is built with FTS5.
+// A database indexed by a build with FTS5 cannot be used by a build without it,
+// as the triggers keeping the index in sync would fail.
+func (s *SQLStore) detectFullText() error {
+	if s.dialect.name == postgresDialect.name {
+		s.fullText = true
+		return nil
+	}
+
+	var indexed int
+	if err := s.queryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'posts_fts'").Scan(&indexed); err != nil {
+		return err
+	}
+	available, err := hasModule(s.db, "fts5")
+	if err != nil {
+		return err
+	}
+
+	if indexed > 0 && !available {
+		return errors.New("database has a full-text index but SQLite is built without FTS5, build with -tags sqlite_fts5")
+	}
+	s.fullText = indexed > 0
+
+	return nil
+}
+
+// MigrateDown reverts the last n applied migrations
+func (s *SQLStore) MigrateDown(n int) error {
+	return migrateDown(s.db, s.dialect, migrations, n)
+}
+
+// MigrationStatus lists all migrations and whether they are applied
+func (s *SQLStore) MigrationStatus() ([]MigrationStatus, error) {
+	return migrationStatus(s.db, s.dialect, migrations)
+}
+
+// exec runs query after rebinding its placeholders
+func (s *SQLStore) exec(query string, args ...interface{}) (sql.Result, error) {
+	return s.db.Exec(s.dialect.rebind(query), args...)
+}
+
+// query runs query after rebinding its placeholders
+func (s *SQLStore) query(query string, args ...interface{}) (*sql.Rows, error) {
+	return s.db.Query(s.dialect.rebind(query), args...)
+}
+
+// queryRow runs query after rebinding its placeholders
+func (s *SQLStore) queryRow(query string, args ...interface{}) *sql.Row {
+	return s.db.QueryRow(s.dialect.rebind(query), args...)
+}
+
+// postBatchSize is the maximum number of post ids bound in a single IN list,
+// which keeps the queries loading the relations of many posts under the variable limit of SQLite
+const postBatchSize = 500
+
+// inPostBatches calls load with the posts in batches of at most postBatchSize posts,
+// the placeholders of their ids, e.g. "(?,?)", and the ids
+func inPostBatches(posts []core.Post, load func(batch []core.Post, in string, ids []interface{}) error) error {
+	for len(posts) > 0 {
+		n := len(posts)
+		if n > postBatchSize {
+			n = postBatchSize
+		}
+		batch := posts[:n]
+		posts = posts[n:]
+
+		ids := make([]interface{}, 0, n)
+		for _, post := range batch {
+			ids = append(ids, post.ID)
+		}
+		if err := load(batch, "(?"+strings.Repeat(",?", n-1)+")", ids); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FindUser function searches database for a specific user.
+// Returns a pointer to the core.User if it finds
+// nil otherwise.
+func (s *SQLStore) FindUser(username string) (*core.User, error) {
+	var user core.User
+
+	err := s.queryRow("SELECT username, password, is_admin, COALESCE(joined_at, 0) FROM users WHERE username = ?", username).
+		Scan(&user.Username, &user.Password, &user.Admin, &user.JoinedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// AddUser function adds the username and password into users table in database.
+// Join date of the user is set to now.
+func (s *SQLStore) AddUser(user *core.User) error {
+	joinedAt := time.Now().Unix()
+	_, err := s.exec("INSERT INTO users(username,password,joined_at) values (?, ?, ?)", user.Username, user.Password, joinedAt)
+	if err != nil {
+		return err
+	}
+
+	user.JoinedAt = joinedAt
+	return nil
+}
+
+// SetAdmin function grants or revokes admin rights of the user
+func (s *SQLStore) SetAdmin(username string, admin bool) error {
+	result, err := s.exec("UPDATE users SET is_admin = ? WHERE username = ?", admin, username)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// BlacklistToken function expects a string, jti, and int64, expire time.
+// Adds jti and expire time of the JWT into blacklist table in database.
+func (s *SQLStore) BlacklistToken(jti string, expiresAt int64) error {
+	_, err := s.exec("INSERT INTO blacklist values (?,?)", jti, expiresAt)
+	return err
+}
+
+// FindJTI function searches the given jti string in database.
+func (s *SQLStore) FindJTI(jti string) (bool, error) {
+	var found int
+	err := s.queryRow("SELECT 1 FROM blacklist WHERE jti = ?", jti).Scan(&found)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// PurgeExpiredTokens function deletes the jtis whose token expired at or before now
+func (s *SQLStore) PurgeExpiredTokens(now int64) (int64, error) {
+	result, err := s.exec(`DELETE FROM blacklist WHERE "expiresAt" <= ?`, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,59 @@
+package database
+
+import "github.com/furkanpala/post-app/internal/core"
+
+// ListTags returns the tags of the posts which are not deleted
+// with the number of posts tagged with them, most used first
+func (s *SQLStore) ListTags() ([]core.Tag, error) {
+	var tags []core.Tag
+
+	rows, err := s.query(`SELECT t.name, COUNT(*) FROM tags t
+		JOIN post_tags pt ON pt.tag_id = t.id
+		JOIN posts p ON p.id = pt.post_id
+		WHERE ` + visiblePost + `
+		GROUP BY t.name ORDER BY COUNT(*) DESC, t.name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tag core.Tag
+		if err := rows.Scan(&tag.Name, &tag.Count); err != nil {
+			return tags, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// loadTags fills in the tags of the posts, in alphabetical order
+func (s *SQLStore) loadTags(posts []core.Post) error {
+	return inPostBatches(posts, func(batch []core.Post, in string, ids []interface{}) error {
+		index := make(map[int]int, len(batch))
+		for i, post := range batch {
+			index[post.ID] = i
+		}
+
+		rows, err := s.query(`SELECT pt.post_id, t.name FROM post_tags pt JOIN tags t ON t.id = pt.tag_id
+			WHERE pt.post_id IN `+in+` ORDER BY pt.post_id, t.name`, ids...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var postID int
+			var tag string
+			if err := rows.Scan(&postID, &tag); err != nil {
+				return err
+			}
+
+			i := index[postID]
+			batch[i].Tags = append(batch[i].Tags, tag)
+		}
+
+		return rows.Err()
+	})
+}
@@ -0,0 +1,141 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/furkanpala/post-app/internal/core"
+)
+
+var (
+	// ErrNotFound is returned when the requested record does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrVersionConflict is returned when a record was changed since the given version was read
+	ErrVersionConflict = errors.New("version conflict")
+	// ErrInvalidAttachment is returned when a post refers to an attachment which does not exist,
+	// is uploaded by another user or is attached to another post
+	ErrInvalidAttachment = errors.New("invalid attachment")
+)
+
+// Store is the persistence layer used by the HTTP handlers.
+// It keeps users, posts with their revisions, comments, reactions, tags and attachments
+// and the blacklist of revoked refresh tokens.
+type Store interface {
+	// FindUser returns the user with given username, nil if there is no such user.
+	FindUser(username string) (*core.User, error)
+	// AddUser adds the user into the store and sets the join date of the user.
+	AddUser(user *core.User) error
+	// SetAdmin grants or revokes admin rights of the user.
+	SetAdmin(username string, admin bool) error
+
+	// Follow makes follower follow followee, does nothing if it already does.
+	Follow(follower, followee string) error
+	// Unfollow makes follower stop following followee, does nothing if it does not follow.
+	Unfollow(follower, followee string) error
+	// ListFollowers returns the users following the user, most recent first.
+	ListFollowers(username string) ([]string, error)
+	// ListFollowing returns the users followed by the user, most recent first.
+	ListFollowing(username string) ([]string, error)
+	// CountFollowers returns the number of users following the user.
+	CountFollowers(username string) (int, error)
+	// CountFollowing returns the number of users followed by the user.
+	CountFollowing(username string) (int, error)
+
+	// BlacklistToken adds the jti and expire time of a JWT into the blacklist.
+	BlacklistToken(jti string, expiresAt int64) error
+	// FindJTI reports whether the given jti is in the blacklist.
+	FindJTI(jti string) (bool, error)
+	// PurgeExpiredTokens deletes the jtis which expired at or before now,
+	// a unix time, and returns the number of deleted jtis.
+	PurgeExpiredTokens(now int64) (int64, error)
+
+	// CountPosts returns the number of posts selected by q, ignoring its paging fields.
+	CountPosts(q PostQuery) (int, error)
+	// ListingState returns the number of posts selected by q and when they were last changed,
+	// ignoring its paging fields.
+	ListingState(q PostQuery) (ListingState, error)
+	// GetAllPosts returns all the posts, newest first.
+	GetAllPosts() ([]core.Post, error)
+	// ListPosts returns the posts selected by q, newest first.
+	ListPosts(q PostQuery) ([]core.Post, error)
+	// SearchPosts returns the posts matching q, best match first.
+	SearchPosts(q SearchQuery) ([]core.SearchResult, error)
+	// AddPost adds a post with its tags into the store and sets its ID and date.
+	// Post is published unless its status says otherwise.
+	// Content of the post is rendered from Markdown into ContentHTML.
+	// Attachments of the post are attached to it, ErrInvalidAttachment is returned
+	// and nothing is added if any of them cannot be attached.
+	// Tags must be normalized with core.NormalizeTags.
+	AddPost(post *core.Post) error
+	// FindPost returns the post with given id, ErrNotFound if there is no such post.
+	FindPost(id int) (*core.Post, error)
+	// UpdatePost saves title, content, status and publishing time of post
+	// if the stored post is still at version and renders the content again.
+	// Date of the post becomes the current time when it is published.
+	// The replaced title and content are kept as revision number version of the post.
+	// Returns ErrVersionConflict if the post was changed since.
+	UpdatePost(post *core.Post, version int) error
+
+	// ListDrafts returns the drafts and scheduled posts of the user which are not deleted,
+	// most recently created first.
+	// Like deleted posts, unpublished posts are left out of the listings, counts and search.
+	ListDrafts(username string) ([]core.Post, error)
+	// PublishScheduledPosts publishes the scheduled posts whose publishing time is at or before now,
+	// a unix time, and returns the published posts.
+	// Date of a published post becomes now, so posts are dated in the order they become visible.
+	PublishScheduledPosts(now int64) ([]core.Post, error)
+
+	// DeletePost moves the post into the trash.
+	// Deleted posts are left out of the listings, counts and search
+	// but FindPost still returns them with DeletedAt set.
+	DeletePost(id int) error
+	// RestorePost takes the post out of the trash.
+	RestorePost(id int) error
+	// ListTrash returns the deleted posts of the user, most recently deleted first.
+	ListTrash(username string) ([]core.Post, error)
+	// PurgeDeletedPosts removes the posts deleted at or before the given unix time
+	// together with their attachments for good, returns the number of removed posts
+	// and the ids of the removed attachments, whose files are left to the caller.
+	PurgeDeletedPosts(before int64) (int64, []string, error)
+
+	// ListRevisions returns the versions of the post replaced by edits, oldest first.
+	ListRevisions(postID int) ([]core.Revision, error)
+	// FindRevision returns the nth version of the post, ErrNotFound if it is not a replaced version.
+	FindRevision(postID, n int) (*core.Revision, error)
+
+	// ListComments returns the top level comments selected by q followed by all of their replies,
+	// both ordered oldest first.
+	ListComments(q CommentQuery) ([]core.Comment, error)
+	// CountComments returns the number of top level comments of the post.
+	CountComments(postID int) (int, error)
+	// FindComment returns the comment with given id, ErrNotFound if there is no such comment.
+	FindComment(id int) (*core.Comment, error)
+	// AddComment adds the comment into the store and sets its ID and date.
+	AddComment(comment *core.Comment) error
+	// UpdateComment saves the content of the comment, ErrNotFound if the comment is deleted.
+	UpdateComment(comment *core.Comment) error
+	// DeleteComment clears the content of the comment and marks it deleted.
+	DeleteComment(id int) error
+
+	// AddAttachment adds an attachment, which is not attached to any post yet, into the store.
+	AddAttachment(a *core.Attachment) error
+	// FindAttachment returns the attachment with given id, ErrNotFound if there is no such attachment.
+	FindAttachment(id string) (*core.Attachment, error)
+	// PurgeUnattachedAttachments removes the attachments uploaded at or before the given unix time
+	// which are not attached to any post and returns their ids, their files are left to the caller.
+	PurgeUnattachedAttachments(before int64) ([]string, error)
+
+	// ListTags returns the tags of the posts which are not deleted
+	// with the number of posts tagged with them, most used first.
+	ListTags() ([]core.Tag, error)
+
+	// AddReaction adds the reaction of the user to the post, does nothing if it is already added.
+	AddReaction(postID int, username, reaction string) error
+	// RemoveReaction removes the reaction of the user from the post, does nothing if there is no such reaction.
+	RemoveReaction(postID int, username, reaction string) error
+	// LoadReactions fills in the reaction counts of the posts
+	// and, if username is not empty, the reactions the user gave to them.
+	LoadReactions(posts []core.Post, username string) error
+
+	// Close releases the resources held by the store.
+	Close() error
+}
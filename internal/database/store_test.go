@@ -0,0 +1,332 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/furkanpala/post-app/internal/core"
+)
+
+// postgresTestURL names the environment variable holding the postgres:// URL of a database
+// the tests also run SQLStore against. Each test creates and drops its own schema in it.
+const postgresTestURL = "POST_APP_TEST_POSTGRES_URL"
+
+// forEachStore runs test on an empty MemoryStore, an empty SQLite SQLStore
+// and, if postgresTestURL is set, an empty PostgreSQL SQLStore
+func forEachStore(t *testing.T, test func(t *testing.T, store Store)) {
+	t.Run("memory", func(t *testing.T) {
+		test(t, NewMemoryStore())
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		store, remove := openSQLiteFile(t)
+		defer remove()
+
+		test(t, store)
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		dsn := os.Getenv(postgresTestURL)
+		if dsn == "" {
+			t.Skip(postgresTestURL + " is not set")
+		}
+
+		store, drop := openPostgresSchema(t, dsn)
+		defer drop()
+		defer store.Close()
+		if err := store.MigrateUp(); err != nil {
+			t.Fatal(err)
+		}
+
+		test(t, store)
+	})
+}
+
+// openSQLiteFile opens a migrated store on a new SQLite file, remove closes the store and removes the file
+func openSQLiteFile(t *testing.T) (store *SQLStore, remove func()) {
+	dir, err := ioutil.TempDir("", "post-app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = OpenSQLite(filepath.Join(dir, "post-app.db"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	remove = func() {
+		store.Close()
+		os.RemoveAll(dir)
+	}
+
+	if err := store.MigrateUp(); err != nil {
+		remove()
+		t.Fatal(err)
+	}
+
+	return store, remove
+}
+
+// openPostgresSchema creates a new schema in the PostgreSQL database at dsn
+// and opens a store using it, drop drops the schema with everything in it
+func openPostgresSchema(t *testing.T, dsn string) (store *SQLStore, drop func()) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatalf("%s: %v", postgresTestURL, err)
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := fmt.Sprintf("post_app_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		admin.Close()
+		t.Fatal(err)
+	}
+	drop = func() {
+		if _, err := admin.Exec("DROP SCHEMA " + schema + " CASCADE"); err != nil {
+			t.Errorf("dropping schema %s: %v", schema, err)
+		}
+		admin.Close()
+	}
+
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+
+	store, err = OpenPostgres(u.String())
+	if err != nil {
+		drop()
+		t.Fatal(err)
+	}
+
+	return store, drop
+}
+
+// addPost adds a post into store and fails the test on error
+func addPost(t *testing.T, store Store, post core.Post) core.Post {
+	t.Helper()
+	if post.Status == "" {
+		post.Status = core.PostPublished
+	}
+	if err := store.AddPost(&post); err != nil {
+		t.Fatalf("AddPost: %v", err)
+	}
+
+	return post
+}
+
+func TestUsers(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		if err := store.AddUser(&core.User{Username: "alice", Password: "secret"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.SetAdmin("alice", true); err != nil {
+			t.Fatal(err)
+		}
+
+		user, err := store.FindUser("alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user == nil || user.Password != "secret" || !user.Admin || user.JoinedAt == 0 {
+			t.Fatalf("FindUser returned %+v, want admin alice with a join date", user)
+		}
+
+		if user, err := store.FindUser("bob"); err != nil || user != nil {
+			t.Fatalf("FindUser of an unknown user returned %+v, %v, want nil", user, err)
+		}
+	})
+}
+
+func TestBlacklist(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		if err := store.BlacklistToken("expired", 100); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.BlacklistToken("valid", 300); err != nil {
+			t.Fatal(err)
+		}
+
+		if found, err := store.FindJTI("valid"); err != nil || !found {
+			t.Fatalf("FindJTI of a blacklisted jti returned %v, %v, want true", found, err)
+		}
+		if found, err := store.FindJTI("unknown"); err != nil || found {
+			t.Fatalf("FindJTI of an unknown jti returned %v, %v, want false", found, err)
+		}
+
+		purged, err := store.PurgeExpiredTokens(200)
+		if err != nil || purged != 1 {
+			t.Fatalf("PurgeExpiredTokens returned %d, %v, want 1", purged, err)
+		}
+		if found, _ := store.FindJTI("expired"); found {
+			t.Fatal("expired jti is still in the blacklist")
+		}
+		if found, _ := store.FindJTI("valid"); !found {
+			t.Fatal("jti which did not expire is purged")
+		}
+	})
+}
+
+func TestAddAndCountPosts(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		first := addPost(t, store, core.Post{Title: "First", Content: "Content", User: "alice"})
+		second := addPost(t, store, core.Post{Title: "Second", Content: "Content", User: "bob"})
+		if first.ID == 0 || second.ID <= first.ID {
+			t.Fatalf("posts got ids %d and %d, want increasing ids", first.ID, second.ID)
+		}
+
+		found, err := store.FindPost(second.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.Title != "Second" || found.User != "bob" {
+			t.Fatalf("FindPost returned %+v, want the second post", found)
+		}
+		if _, err := store.FindPost(second.ID + 1); err != ErrNotFound {
+			t.Fatalf("FindPost of an unknown post returned %v, want ErrNotFound", err)
+		}
+
+		if count, err := store.CountPosts(PostQuery{}); err != nil || count != 2 {
+			t.Fatalf("CountPosts returned %d, %v, want 2", count, err)
+		}
+		if count, err := store.CountPosts(PostQuery{Author: "bob"}); err != nil || count != 1 {
+			t.Fatalf("CountPosts of bob returned %d, %v, want 1", count, err)
+		}
+	})
+}
+
+func TestListPostsCursors(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		var posts []core.Post
+		for i := 0; i < 5; i++ {
+			posts = append(posts, addPost(t, store, core.Post{Title: "Post", Content: "Content", User: "alice"}))
+		}
+
+		after := CursorOf(posts[3])
+		older, err := store.ListPosts(PostQuery{After: &after})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(older) != 3 || older[0].ID != posts[2].ID || older[2].ID != posts[0].ID {
+			t.Fatalf("posts after cursor of %d are %v, want 3 older posts newest first", posts[3].ID, ids(older))
+		}
+
+		publishedAfter := CursorOf(posts[1])
+		newer, err := store.ListPosts(PostQuery{PublishedAfter: &publishedAfter, Sort: SortOldest})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(newer) != 3 || newer[0].ID != posts[2].ID || newer[2].ID != posts[4].ID {
+			t.Fatalf("posts published after %d are %v, want 3 newer posts oldest first", posts[1].ID, ids(newer))
+		}
+	})
+}
+
+func TestListingStateChanges(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		post := addPost(t, store, core.Post{Title: "Post", Content: "Content", User: "alice"})
+		addPost(t, store, core.Post{Title: "Other", Content: "Content", User: "alice"})
+
+		if err := store.AddComment(&core.Comment{PostID: post.ID, User: "bob", Content: "Nice"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.AddReaction(post.ID, "bob", "like"); err != nil {
+			t.Fatal(err)
+		}
+
+		state, err := store.ListingState(PostQuery{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state.Count != 2 || state.Comments != 1 || state.Reactions != 1 {
+			t.Fatalf("state is %+v, want 2 posts, 1 comment and 1 reaction", state)
+		}
+
+		// Changes which leave no date on the listed posts still move LastModified
+		changes := []struct {
+			name   string
+			change func() error
+		}{
+			{"deleting the post", func() error { return store.DeletePost(post.ID) }},
+			{"restoring the post", func() error { return store.RestorePost(post.ID) }},
+			{"removing the reaction", func() error { return store.RemoveReaction(post.ID, "bob", "like") }},
+		}
+		for _, c := range changes {
+			nextSecond()
+			if err := c.change(); err != nil {
+				t.Fatalf("%s: %v", c.name, err)
+			}
+
+			changed, err := store.ListingState(PostQuery{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if changed.LastModified <= state.LastModified {
+				t.Fatalf("LastModified is %d after %s, want later than %d", changed.LastModified, c.name, state.LastModified)
+			}
+			state = changed
+		}
+	})
+}
+
+func TestTrashAndPurge(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		post := addPost(t, store, core.Post{Title: "Post", Content: "Content", User: "alice"})
+
+		if err := store.DeletePost(post.ID); err != nil {
+			t.Fatal(err)
+		}
+		if trash, err := store.ListTrash("alice"); err != nil || len(trash) != 1 {
+			t.Fatalf("trash has %d posts, error %v, want the deleted post", len(trash), err)
+		}
+		if count, err := store.CountPosts(PostQuery{}); err != nil || count != 0 {
+			t.Fatalf("%d posts are listed, error %v, want none", count, err)
+		}
+		if err := store.RestorePost(post.ID); err != nil {
+			t.Fatal(err)
+		}
+		if count, err := store.CountPosts(PostQuery{}); err != nil || count != 1 {
+			t.Fatalf("%d posts are listed, error %v, want the restored post", count, err)
+		}
+
+		if err := store.DeletePost(post.ID); err != nil {
+			t.Fatal(err)
+		}
+		purged, _, err := store.PurgeDeletedPosts(1 << 40)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if purged != 1 {
+			t.Fatalf("purged %d posts, want the deleted post", purged)
+		}
+		if _, err := store.FindPost(post.ID); err != ErrNotFound {
+			t.Fatalf("FindPost of a purged post returned %v, want ErrNotFound", err)
+		}
+	})
+}
+
+// nextSecond waits until the next second starts, so that the changes made after it
+// are later than the ones made before at the one second resolution of the stores
+func nextSecond() {
+	now := time.Now()
+	time.Sleep(now.Truncate(time.Second).Add(time.Second).Sub(now))
+}
+
+// ids returns the ids of the posts
+func ids(posts []core.Post) []int {
+	ids := make([]int, 0, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+	}
+
+	return ids
+}
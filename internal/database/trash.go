@@ -0,0 +1,47 @@
+package database
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/furkanpala/post-app/internal/storage"
+)
+
+// RunTrashJanitor removes the posts which have been in the trash longer than retention
+// and the attachments which have not been attached to a post within uploadRetention
+// from store every interval, and their files from blobs.
+// It blocks until stop is closed, so it is meant to be run on its own goroutine.
+func RunTrashJanitor(store Store, blobs storage.BlobStore, retention, uploadRetention, interval time.Duration,
+	stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			_, attachmentIDs, err := store.PurgeDeletedPosts(now.Add(-retention).Unix())
+			if err != nil {
+				log.Printf("Trash purge error: %v\n", err)
+			}
+			deleteBlobs(blobs, attachmentIDs)
+
+			attachmentIDs, err = store.PurgeUnattachedAttachments(now.Add(-uploadRetention).Unix())
+			if err != nil {
+				log.Printf("Unattached attachment purge error: %v\n", err)
+			}
+			deleteBlobs(blobs, attachmentIDs)
+		}
+	}
+}
+
+// deleteBlobs deletes the files of the attachments with given ids from blobs
+func deleteBlobs(blobs storage.BlobStore, ids []string) {
+	for _, id := range ids {
+		if err := blobs.Delete(id); err != nil && !os.IsNotExist(err) {
+			log.Printf("Attachment delete error: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package diff
+
+import (
+	"errors"
+	"strings"
+)
+
+// MaxCells limits the size of the table of the longest common subsequence,
+// which is the product of the numbers of the lines that differ in the texts
+const MaxCells = 1 << 22
+
+// ErrTooLarge is returned when the texts differ in too many lines to compare
+var ErrTooLarge = errors.New("texts differ in too many lines to compare")
+
+// Op tells how a line of a diff changes the old text
+type Op string
+
+const (
+	// Equal lines are in both texts
+	Equal Op = "equal"
+	// Insert lines are only in the new text
+	Insert Op = "insert"
+	// Delete lines are only in the old text
+	Delete Op = "delete"
+)
+
+// Line is a line of a diff
+type Line struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// Lines returns the line-based diff which turns old into new.
+// It is built from the longest common subsequence of the lines,
+// deletions come before insertions where lines are replaced.
+// Common leading and trailing lines are left out of the table,
+// ErrTooLarge is returned if the rest is larger than MaxCells.
+func Lines(old, new string) ([]Line, error) {
+	a, b := splitLines(old), splitLines(new)
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]Line, 0, len(a)+len(b)-prefix-suffix)
+	for _, text := range a[:prefix] {
+		lines = append(lines, Line{Op: Equal, Text: text})
+	}
+
+	changed, err := changedLines(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+	if err != nil {
+		return nil, err
+	}
+	lines = append(lines, changed...)
+
+	for _, text := range a[len(a)-suffix:] {
+		lines = append(lines, Line{Op: Equal, Text: text})
+	}
+
+	return lines, nil
+}
+
+// changedLines returns the diff which turns a into b from their longest common subsequence
+func changedLines(a, b []string) ([]Line, error) {
+	if len(a) > 0 && len(b) > MaxCells/len(a) {
+		return nil, ErrTooLarge
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	lines := make([]Line, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, Line{Op: Equal, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, Line{Op: Delete, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, Line{Op: Insert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, Line{Op: Delete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, Line{Op: Insert, Text: b[j]})
+	}
+
+	return lines, nil
+}
+
+// splitLines splits s into its lines, empty text has no lines
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
@@ -7,3 +7,32 @@ var AccessTokenSecret = os.Getenv("ACCESS_TOKEN_SECRET")
 
 // RefreshTokenSecret holds the value of refresh token secret key
 var RefreshTokenSecret = os.Getenv("REFRESH_TOKEN_SECRET")
+
+// DatabaseURL holds the DSN of the database.
+// A postgres:// URL selects PostgreSQL, anything else is an SQLite file path.
+var DatabaseURL = os.Getenv("DATABASE_URL")
+
+// BlacklistPurgeInterval holds how often expired jtis are deleted from the blacklist, e.g. "1h"
+var BlacklistPurgeInterval = os.Getenv("BLACKLIST_PURGE_INTERVAL")
+
+// BlacklistCacheTTL holds how long a blacklist lookup is cached, e.g. "1m"
+var BlacklistCacheTTL = os.Getenv("BLACKLIST_CACHE_TTL")
+
+// TrashRetention holds how long deleted posts are kept before they are purged, e.g. "720h"
+var TrashRetention = os.Getenv("TRASH_RETENTION")
+
+// UploadRetention holds how long uploaded attachments are kept while they are not attached to a post, e.g. "24h"
+var UploadRetention = os.Getenv("UPLOAD_RETENTION")
+
+// PublishInterval holds how often scheduled posts are checked for publishing, e.g. "1m"
+var PublishInterval = os.Getenv("PUBLISH_INTERVAL")
+
+// Reactions holds the comma separated types of reaction users can give to posts, e.g. "like,laugh"
+var Reactions = os.Getenv("REACTIONS")
+
+// AttachmentsDir holds the directory the files of attachments are stored in
+var AttachmentsDir = os.Getenv("ATTACHMENTS_DIR")
+
+// PublicURL holds the URL the server is reached at, e.g. "https://board.example.com".
+// It is used for the absolute links in feeds.
+var PublicURL = os.Getenv("PUBLIC_URL")
@@ -0,0 +1,203 @@
+// Package feed renders lists of posts as RSS 2.0 and Atom feeds.
+package feed
+
+import (
+	"encoding/xml"
+	"strconv"
+	"time"
+
+	"github.com/furkanpala/post-app/internal/core"
+)
+
+// Feed is a list of posts to render as a feed
+type Feed struct {
+	// Title of the feed
+	Title string
+	// BaseURL is the absolute URL of the server without a trailing slash, e.g. https://board.example.com
+	BaseURL string
+	// IDBase is prefixed to the paths of the feed and the posts to form their ids.
+	// Ids must never change, so it is the public URL of the server or a fixed tag URI.
+	IDBase string
+	// Path is the path of the feed itself, e.g. /feed.atom
+	Path string
+	// Posts of the feed, newest first
+	Posts []core.Post
+}
+
+// postURL returns the absolute URL of the page of the post in the web app
+func (f Feed) postURL(post core.Post) string {
+	return f.BaseURL + "/post/" + strconv.Itoa(post.ID)
+}
+
+// postID returns the unique id of the post in the feed
+func (f Feed) postID(post core.Post) string {
+	return f.IDBase + "/posts/" + strconv.Itoa(post.ID)
+}
+
+// updated returns the last time a post of the feed is added or edited
+func (f Feed) updated() int64 {
+	var updated int64
+	for _, post := range f.Posts {
+		if t := postUpdated(post); t > updated {
+			updated = t
+		}
+	}
+
+	return updated
+}
+
+// postUpdated returns the time of the last edit of the post, the time it is added if it is never edited
+func postUpdated(post core.Post) int64 {
+	if post.EditedAt > post.Date {
+		return post.EditedAt
+	}
+
+	return post.Date
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Self          atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Categories  []string `xml:"category"`
+	Description string   `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSS renders the feed as an RSS 2.0 document.
+// Descriptions hold the rendered HTML of the posts, escaped as XML text.
+func RSS(f Feed) ([]byte, error) {
+	channel := rssChannel{
+		Title:       f.Title,
+		Link:        f.BaseURL + "/",
+		Description: f.Title,
+		Self:        atomLink{Href: f.BaseURL + f.Path, Rel: "self", Type: "application/rss+xml"},
+	}
+	if updated := f.updated(); updated != 0 {
+		channel.LastBuildDate = time.Unix(updated, 0).UTC().Format(time.RFC1123Z)
+	}
+
+	for _, post := range f.Posts {
+		channel.Items = append(channel.Items, rssItem{
+			Title:       post.Title,
+			Link:        f.postURL(post),
+			GUID:        rssGUID{IsPermaLink: false, Value: f.postID(post)},
+			PubDate:     time.Unix(post.Date, 0).UTC().Format(time.RFC1123Z),
+			Categories:  post.Tags,
+			Description: post.ContentHTML,
+		})
+	}
+
+	return marshal(rss{Version: "2.0", Atom: atomNamespace, Channel: channel})
+}
+
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       atomLink       `xml:"link"`
+	Author     atomAuthor     `xml:"author"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Categories []atomCategory `xml:"category"`
+	Content    atomContent    `xml:"content"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Atom renders the feed as an Atom document.
+// Contents hold the rendered HTML of the posts, escaped as XML text.
+func Atom(f Feed) ([]byte, error) {
+	doc := atomFeed{
+		Xmlns:   atomNamespace,
+		ID:      f.IDBase + f.Path,
+		Title:   f.Title,
+		Updated: atomTime(f.updated()),
+		Links: []atomLink{
+			{Href: f.BaseURL + f.Path, Rel: "self", Type: "application/atom+xml"},
+			{Href: f.BaseURL + "/", Rel: "alternate", Type: "text/html"},
+		},
+	}
+
+	for _, post := range f.Posts {
+		entry := atomEntry{
+			ID:        f.postID(post),
+			Title:     post.Title,
+			Link:      atomLink{Href: f.postURL(post), Rel: "alternate"},
+			Author:    atomAuthor{Name: post.User},
+			Published: atomTime(post.Date),
+			Updated:   atomTime(postUpdated(post)),
+			Content:   atomContent{Type: "html", Value: post.ContentHTML},
+		}
+		for _, tag := range post.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return marshal(doc)
+}
+
+// atomTime formats the unix time t as an RFC 3339 timestamp
+func atomTime(t int64) string {
+	return time.Unix(t, 0).UTC().Format(time.RFC3339)
+}
+
+// marshal encodes v as an XML document with the XML declaration
+func marshal(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
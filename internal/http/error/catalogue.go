@@ -0,0 +1,85 @@
+package httperror
+
+// TypeURIPrefix is prefixed to the error types to form the type URIs of problem details
+const TypeURIPrefix = "/problems/"
+
+// Catalogue of error types.
+// Types are stable and machine-readable, clients should branch on them instead of titles.
+const (
+	// TypeInternal is an unexpected failure of the server, e.g. a database error
+	TypeInternal = "internal_error"
+	// TypeInvalidJSON is a request body which is not valid JSON or has unknown fields
+	TypeInvalidJSON = "invalid_json"
+
+	// TypeMissingToken is a request without a bearer access token to a route requiring one
+	TypeMissingToken = "missing_token"
+	// TypeInvalidToken is an access or refresh token which is malformed, expired or signed with another secret
+	TypeInvalidToken = "invalid_token"
+	// TypeRevokedToken is an access or refresh token which is logged out
+	TypeRevokedToken = "revoked_token"
+	// TypeMissingRefreshToken is a request without the refresh token cookie
+	TypeMissingRefreshToken = "missing_refresh_token"
+	// TypeInvalidCredentials is a login with an unknown username or a wrong password
+	TypeInvalidCredentials = "invalid_credentials"
+	// TypeUserExists is a registration with a username which is taken
+	TypeUserExists = "user_exists"
+	// TypeForbidden is a change of a post or a comment by a user other than its sender or an admin
+	TypeForbidden = "forbidden"
+
+	// TypeInvalidRegistration is a registration failing validation, see Errors
+	TypeInvalidRegistration = "invalid_registration"
+	// TypeInvalidPost is a new or edited post failing validation, see Errors
+	TypeInvalidPost = "invalid_post"
+	// TypeInvalidComment is a new or edited comment failing validation, see Errors
+	TypeInvalidComment = "invalid_comment"
+	// TypeInvalidFilter is a listing of posts with invalid query parameters, see Errors
+	TypeInvalidFilter = "invalid_filter"
+
+	// TypeInvalidPage is a page parameter which is not a positive integer
+	TypeInvalidPage = "invalid_page"
+	// TypeInvalidCursor is a cursor parameter which is not a cursor given by the server
+	TypeInvalidCursor = "invalid_cursor"
+	// TypeInvalidTag is a tag parameter which is not a valid tag, or more tag parameters than a listing can be filtered by
+	TypeInvalidTag = "invalid_tag"
+	// TypeInvalidSearch is a search without a query
+	TypeInvalidSearch = "invalid_search"
+	// TypeInvalidPostID is a post id which is not a positive integer
+	TypeInvalidPostID = "invalid_post_id"
+	// TypeInvalidCommentID is a comment id which is not a positive integer
+	TypeInvalidCommentID = "invalid_comment_id"
+	// TypeInvalidRevision is a revision number which is not a positive integer
+	// or a diff from a revision which is not before the other
+	TypeInvalidRevision = "invalid_revision"
+	// TypeDiffTooLarge is a diff of revisions which differ in too many lines to compare
+	TypeDiffTooLarge = "diff_too_large"
+	// TypeInvalidReaction is a reaction type which is not allowed
+	TypeInvalidReaction = "invalid_reaction"
+	// TypeInvalidFollow is a user following themselves
+	TypeInvalidFollow = "invalid_follow"
+	// TypeInvalidAttachment is an upload without a file field
+	TypeInvalidAttachment = "invalid_attachment"
+	// TypeAttachmentTooLarge is an upload above the size or pixel limits
+	TypeAttachmentTooLarge = "attachment_too_large"
+	// TypeUnsupportedAttachment is an upload which is not a JPEG, PNG or GIF image
+	TypeUnsupportedAttachment = "unsupported_attachment"
+	// TypeInvalidLastEventID is a Last-Event-ID header which is not a post id
+	TypeInvalidLastEventID = "invalid_last_event_id"
+
+	// TypePreconditionRequired is an edit of a post without If-Match header
+	TypePreconditionRequired = "precondition_required"
+	// TypePreconditionFailed is an edit of a post which has been changed since it was read
+	TypePreconditionFailed = "precondition_failed"
+
+	// TypePageNotFound is a page past the last page of a listing
+	TypePageNotFound = "page_not_found"
+	// TypePostNotFound is a post which does not exist or is not visible to the user
+	TypePostNotFound = "post_not_found"
+	// TypeCommentNotFound is a comment which does not exist on the post
+	TypeCommentNotFound = "comment_not_found"
+	// TypeRevisionNotFound is a revision which does not exist
+	TypeRevisionNotFound = "revision_not_found"
+	// TypeUserNotFound is a user who does not exist
+	TypeUserNotFound = "user_not_found"
+	// TypeAttachmentNotFound is an attachment which does not exist
+	TypeAttachmentNotFound = "attachment_not_found"
+)
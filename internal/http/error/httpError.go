@@ -1,9 +1,37 @@
 package httperror
 
+import "github.com/furkanpala/post-app/internal/validation"
+
+// HTTPError is the error response of a route.
+// Type is one of the types in the catalogue, Errors lists the invalid fields
+// of a request failing validation.
+// Info is sent to the client, so it must be safe to show.
+// Cause is only logged, it may hold internal details like database errors.
+// It marshals into the legacy format, see Problem for the default one.
 type HTTPError struct {
-	Cause error        `json:"-"`
-	Info  ErrorMessage `json:"message"`
-	Code  int          `json:"code"`
+	Cause  error             `json:"-"`
+	Type   string            `json:"-"`
+	Info   ErrorMessage      `json:"message"`
+	Errors validation.Errors `json:"errors,omitempty"`
+	Code   int               `json:"code"`
+}
+
+// Public returns the error as it is sent to the client of the request with given id.
+// Server errors are reduced to their title and the id of the request,
+// which is logged with the cause, so that nothing internal leaks to clients.
+func (e *HTTPError) Public(requestID string) *HTTPError {
+	if e.Code < 500 {
+		return e
+	}
+
+	return &HTTPError{
+		Type: e.Type,
+		Info: ErrorMessage{
+			Title:  "Internal server error",
+			Detail: "Request id " + requestID,
+		},
+		Code: e.Code,
+	}
 }
 
 type ErrorMessage struct {
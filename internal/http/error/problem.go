@@ -0,0 +1,65 @@
+package httperror
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/furkanpala/post-app/internal/validation"
+)
+
+// Media types of error responses
+const (
+	// ProblemMediaType is the media type of RFC 7807 problem details, the default format
+	ProblemMediaType = "application/problem+json"
+	// LegacyMediaType is accepted by the clients which still read the legacy format,
+	// {"message": {"title", "detail"}, "code"}
+	LegacyMediaType = "application/vnd.post-app.legacy+json"
+)
+
+// Problem is the RFC 7807 problem details of an HTTPError.
+// Code is the type of the error in the catalogue, Type its URI.
+type Problem struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title"`
+	Status   int               `json:"status"`
+	Detail   string            `json:"detail,omitempty"`
+	Instance string            `json:"instance,omitempty"`
+	Code     string            `json:"code,omitempty"`
+	Errors   validation.Errors `json:"errors,omitempty"`
+}
+
+// Problem returns the problem details of the error which occurred on the request with given id
+func (e *HTTPError) Problem(requestID string) Problem {
+	problem := Problem{
+		Type:   "about:blank",
+		Title:  e.Info.Title,
+		Status: e.Code,
+		Detail: e.Info.Detail,
+		Code:   e.Type,
+		Errors: e.Errors,
+	}
+	if e.Type != "" {
+		problem.Type = TypeURIPrefix + e.Type
+	}
+	if len(e.Errors) > 0 {
+		problem.Detail = e.Errors.Error()
+	}
+	if requestID != "" {
+		problem.Instance = "urn:uuid:" + requestID
+	}
+
+	return problem
+}
+
+// WantsLegacy reports whether the Accept header of the request asks for the legacy format
+func WantsLegacy(r *http.Request) bool {
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err == nil && mediaType == LegacyMediaType {
+			return true
+		}
+	}
+
+	return false
+}
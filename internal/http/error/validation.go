@@ -0,0 +1,22 @@
+package httperror
+
+import (
+	"strings"
+
+	"github.com/furkanpala/post-app/internal/validation"
+)
+
+// Invalid returns the Bad Request error of given type of a request failing validation.
+// Detail joins the messages with "|" for the clients which do not read Errors yet.
+func Invalid(errorType, title string, errs validation.Errors) *HTTPError {
+	return &HTTPError{
+		Cause: nil,
+		Type:  errorType,
+		Info: ErrorMessage{
+			Title:  title,
+			Detail: strings.Join(errs.Messages(), "|"),
+		},
+		Errors: errs,
+		Code:   400,
+	}
+}
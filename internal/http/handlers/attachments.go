@@ -0,0 +1,243 @@
+package httphandlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/furkanpala/post-app/internal/core"
+	"github.com/furkanpala/post-app/internal/database"
+	httperror "github.com/furkanpala/post-app/internal/http/error"
+	"github.com/furkanpala/post-app/internal/imaging"
+	"github.com/gorilla/mux"
+	uuid "github.com/satori/go.uuid"
+)
+
+// MaxAttachmentSize is the maximum size of an uploaded file in bytes
+const MaxAttachmentSize = 5 << 20
+
+// UploadAttachment handles the POST requests for /attachments route.
+// The image is read from the file field of a multipart/form-data body.
+// Its type is sniffed from its content and its metadata is stripped before it is stored.
+// Responses with the created attachment, whose id can be given in attachment_ids of a new post.
+func (h *Handler) UploadAttachment(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	// Leaves room for the other parts of the multipart body
+	const maxBodySize = MaxAttachmentSize + 1<<20
+	tooLarge := &httperror.HTTPError{
+		Cause: nil,
+		Type:  httperror.TypeAttachmentTooLarge,
+		Info: httperror.ErrorMessage{
+			Title:  "Attachment too large",
+			Detail: "Attachments can be at most 5 MB",
+		},
+		Code: 413,
+	}
+	if r.ContentLength > maxBodySize {
+		return tooLarge
+	}
+	body := &limitedBody{ReadCloser: http.MaxBytesReader(w, r.Body, maxBodySize), limit: maxBodySize}
+	r.Body = body
+
+	file, _, err := r.FormFile("file")
+	if body.exceeded {
+		return tooLarge
+	}
+	if err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInvalidAttachment,
+			Info: httperror.ErrorMessage{
+				Title:  "Invalid attachment",
+				Detail: "A file field of at most 5 MB is required in a multipart/form-data body",
+			},
+			Code: 400,
+		}
+	}
+	defer file.Close()
+
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	if len(data) > MaxAttachmentSize {
+		return tooLarge
+	}
+
+	img, err := imaging.Clean(data)
+	if err == imaging.ErrUnsupportedType {
+		return &httperror.HTTPError{
+			Cause: nil,
+			Type:  httperror.TypeUnsupportedAttachment,
+			Info: httperror.ErrorMessage{
+				Title:  "Unsupported attachment type",
+				Detail: err.Error(),
+			},
+			Code: 415,
+		}
+	}
+	if err == imaging.ErrTooLarge {
+		return &httperror.HTTPError{
+			Cause: nil,
+			Type:  httperror.TypeAttachmentTooLarge,
+			Info: httperror.ErrorMessage{
+				Title:  "Attachment too large",
+				Detail: err.Error(),
+			},
+			Code: 413,
+		}
+	}
+	if err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	attachment := core.Attachment{
+		ID:          uuid.NewV4().String(),
+		ContentType: img.ContentType,
+		Size:        int64(len(img.Data)),
+		Width:       img.Width,
+		Height:      img.Height,
+		User:        Username(r),
+		Date:        time.Now().Unix(),
+	}
+
+	if err := h.blobs.Put(attachment.ID, bytes.NewReader(img.Data)); err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	if err := h.store.AddAttachment(&attachment); err != nil {
+		h.blobs.Delete(attachment.ID)
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/attachments/"+attachment.ID)
+	w.WriteHeader(201)
+	if err := json.NewEncoder(w).Encode(attachment); err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	return nil
+}
+
+// GetAttachment handles the GET requests for /attachments/{id} route.
+// Files of attachments never change, so they can be cached for good.
+func (h *Handler) GetAttachment(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	attachment, err := h.store.FindAttachment(mux.Vars(r)["id"])
+	if err == database.ErrNotFound {
+		return attachmentNotFound()
+	}
+	if err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	file, err := h.blobs.Open(attachment.ID)
+	if os.IsNotExist(err) {
+		return attachmentNotFound()
+	}
+	if err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("ETag", `"`+attachment.ID+`"`)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, "", time.Unix(attachment.Date, 0), file)
+
+	return nil
+}
+
+// attachmentNotFound returns the error of a request for a missing attachment
+func attachmentNotFound() *httperror.HTTPError {
+	return &httperror.HTTPError{
+		Cause: nil,
+		Type:  httperror.TypeAttachmentNotFound,
+		Info: httperror.ErrorMessage{
+			Title:  "Attachment not found",
+			Detail: "",
+		},
+		Code: 404,
+	}
+}
+
+// limitedBody is a request body read through http.MaxBytesReader
+// which tells whether reading failed because the body is larger than limit
+type limitedBody struct {
+	io.ReadCloser
+	limit    int64
+	read     int64
+	exceeded bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	// http.MaxBytesReader returns limit bytes before its error
+	if err != nil && err != io.EOF && b.read >= b.limit {
+		b.exceeded = true
+	}
+
+	return n, err
+}
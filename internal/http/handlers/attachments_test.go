@@ -0,0 +1,79 @@
+package httphandlers
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/furkanpala/post-app/internal/core"
+)
+
+// multipartFile returns a multipart/form-data body with data in its file field and its content type
+func multipartFile(t *testing.T, data []byte) (*bytes.Buffer, string) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "image.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := form.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return &body, form.FormDataContentType()
+}
+
+func TestUploadAttachment(t *testing.T) {
+	s := newTestServer(t)
+
+	var img bytes.Buffer
+	if err := png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 3, 2))); err != nil {
+		t.Fatal(err)
+	}
+	body, contentType := multipartFile(t, img.Bytes())
+
+	w := s.do("POST", "/attachments", "alice", body.String(), "Content-Type", contentType)
+	expectStatus(t, w, 201)
+	var attachment core.Attachment
+	decode(t, w, &attachment)
+	if attachment.ContentType != "image/png" || attachment.Width != 3 || attachment.Height != 2 {
+		t.Fatalf("uploaded attachment is %+v, want a 3×2 PNG", attachment)
+	}
+	if _, err := s.store.FindAttachment(attachment.ID); err != nil {
+		t.Fatalf("FindAttachment of the upload returned %v", err)
+	}
+
+	body, contentType = multipartFile(t, []byte("<svg onload=alert(1)>"))
+	expectStatus(t, s.do("POST", "/attachments", "alice", body.String(), "Content-Type", contentType), 415)
+
+	expectStatus(t, s.do("POST", "/attachments", "alice", "", "Content-Type", contentType), 400)
+}
+
+func TestUploadAttachmentTooLarge(t *testing.T) {
+	s := newTestServer(t)
+
+	// Larger than the file limit but within the body limit
+	body, contentType := multipartFile(t, make([]byte, MaxAttachmentSize+1))
+	expectStatus(t, s.do("POST", "/attachments", "alice", body.String(), "Content-Type", contentType), 413)
+
+	// Larger than the body limit, with and without Content-Length
+	body, contentType = multipartFile(t, make([]byte, MaxAttachmentSize+2<<20))
+	expectStatus(t, s.do("POST", "/attachments", "alice", body.String(), "Content-Type", contentType), 413)
+
+	r := httptest.NewRequest("POST", "/attachments", io.MultiReader(body))
+	r.Header.Set("Content-Type", contentType)
+	r.Header.Set(testUserHeader, "alice")
+	if r.ContentLength != -1 {
+		t.Fatalf("request has Content-Length %d, want none", r.ContentLength)
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, r)
+	expectStatus(t, w, 413)
+}
@@ -0,0 +1,375 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/furkanpala/post-app/internal/core"
+	"github.com/furkanpala/post-app/internal/database"
+	httperror "github.com/furkanpala/post-app/internal/http/error"
+	"github.com/furkanpala/post-app/internal/http/request"
+	"github.com/furkanpala/post-app/internal/http/response"
+	"github.com/furkanpala/post-app/internal/validation"
+	"github.com/gorilla/mux"
+)
+
+// CommentsPerPage is the number of top level comments on a page of comments
+const CommentsPerPage = 20
+
+// GetComments handles the GET requests for /posts/{id}/comments route.
+// Returns a page of comment threads, oldest first, selected by page query parameter, 1 by default.
+// Every top level comment comes with all of its replies nested.
+func (h *Handler) GetComments(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	id, httpErr := postID(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	page := 1
+	if pageParam := r.URL.Query().Get("page"); pageParam != "" {
+		var err error
+		if page, err = strconv.Atoi(pageParam); err != nil || page <= 0 {
+			return &httperror.HTTPError{
+				Cause: nil,
+				Type:  httperror.TypeInvalidPage,
+				Info: httperror.ErrorMessage{
+					Title:  "Invalid page",
+					Detail: "Page must be an integer greater than zero",
+				},
+				Code: 400,
+			}
+		}
+	}
+
+	if _, httpErr := h.findPost(r, id, false); httpErr != nil {
+		return httpErr
+	}
+
+	count, err := h.store.CountComments(id)
+	if err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	offset := CommentsPerPage * (page - 1)
+	if page > 1 && offset >= count {
+		return &httperror.HTTPError{
+			Cause: nil,
+			Type:  httperror.TypePageNotFound,
+			Info: httperror.ErrorMessage{
+				Title:  "Page not found",
+				Detail: "",
+			},
+			Code: 404,
+		}
+	}
+
+	comments, err := h.store.ListComments(database.CommentQuery{
+		PostID: id,
+		Limit:  CommentsPerPage,
+		Offset: offset,
+	})
+	if err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	threads := core.Threads(comments)
+	responseBody := response.CommentsResponse{
+		Comments: threads,
+		Count:    len(threads),
+	}
+
+	if err := json.NewEncoder(w).Encode(responseBody); err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	return nil
+}
+
+// AddComment handles the POST requests for /posts/{id}/comments route.
+// parent_id of the request body, if given, is the comment replied to.
+// Responses with the created comment.
+func (h *Handler) AddComment(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	id, httpErr := postID(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	var body core.Comment
+	if httpErr := request.DecodeRequestBody(r, &body); httpErr != nil {
+		return httpErr
+	}
+
+	if errs := validation.Comment(&body); errs != nil {
+		return httperror.Invalid(httperror.TypeInvalidComment, "Invalid comment info", errs)
+	}
+
+	if _, httpErr := h.findPost(r, id, false); httpErr != nil {
+		return httpErr
+	}
+
+	comment := core.Comment{
+		PostID:   id,
+		ParentID: body.ParentID,
+		Content:  body.Content,
+		User:     Username(r),
+	}
+
+	if comment.ParentID != 0 {
+		parent, httpErr := h.findComment(r, id, comment.ParentID)
+		if httpErr != nil {
+			return httpErr
+		}
+		if parent.DeletedAt != 0 {
+			return httperror.Invalid(httperror.TypeInvalidComment, "Invalid comment info", validation.Errors{
+				{Field: "parent_id", Code: validation.CodeInvalid, Message: "Deleted comments cannot be replied to"},
+			})
+		}
+	}
+
+	if err := h.store.AddComment(&comment); err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+	if err := json.NewEncoder(w).Encode(comment); err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	return nil
+}
+
+// EditComment handles the PATCH requests for /posts/{id}/comments/{commentID} route.
+// Only the sender of the comment or an admin can edit it.
+// Responses with the edited comment.
+func (h *Handler) EditComment(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	id, httpErr := postID(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	commentID, httpErr := commentID(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	var body core.Comment
+	if httpErr := request.DecodeRequestBody(r, &body); httpErr != nil {
+		return httpErr
+	}
+
+	if errs := validation.Comment(&body); errs != nil {
+		return httperror.Invalid(httperror.TypeInvalidComment, "Invalid comment info", errs)
+	}
+
+	comment, httpErr := h.findComment(r, id, commentID)
+	if httpErr != nil {
+		return httpErr
+	}
+	if comment.DeletedAt != 0 {
+		return commentNotFound()
+	}
+
+	canChange, httpErr := h.canChange(r, comment.User)
+	if httpErr != nil {
+		return httpErr
+	}
+	if !canChange {
+		return &httperror.HTTPError{
+			Cause: nil,
+			Type:  httperror.TypeForbidden,
+			Info: httperror.ErrorMessage{
+				Title:  "Forbidden",
+				Detail: "Only the author of the comment or an admin can edit it",
+			},
+			Code: 403,
+		}
+	}
+
+	comment.Content = body.Content
+	if err := h.store.UpdateComment(comment); err != nil {
+		if err == database.ErrNotFound {
+			return commentNotFound()
+		}
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(comment); err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	return nil
+}
+
+// DeleteComment handles the DELETE requests for /posts/{id}/comments/{commentID} route.
+// Only the sender of the comment or an admin can delete it.
+// Content of the comment is removed but its replies stay in the thread.
+func (h *Handler) DeleteComment(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	id, httpErr := postID(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	commentID, httpErr := commentID(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	comment, httpErr := h.findComment(r, id, commentID)
+	if httpErr != nil {
+		return httpErr
+	}
+	if comment.DeletedAt != 0 {
+		return commentNotFound()
+	}
+
+	canChange, httpErr := h.canChange(r, comment.User)
+	if httpErr != nil {
+		return httpErr
+	}
+	if !canChange {
+		return &httperror.HTTPError{
+			Cause: nil,
+			Type:  httperror.TypeForbidden,
+			Info: httperror.ErrorMessage{
+				Title:  "Forbidden",
+				Detail: "Only the author of the comment or an admin can delete it",
+			},
+			Code: 403,
+		}
+	}
+
+	if err := h.store.DeleteComment(commentID); err != nil {
+		if err == database.ErrNotFound {
+			return commentNotFound()
+		}
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	w.WriteHeader(204)
+	return nil
+}
+
+// findComment returns the comment with given id on the post.
+// Post must not be deleted.
+// Responses with Not Found error if there is no such comment on the post.
+func (h *Handler) findComment(r *http.Request, postID, id int) (*core.Comment, *httperror.HTTPError) {
+	if _, httpErr := h.findPost(r, postID, false); httpErr != nil {
+		return nil, httpErr
+	}
+
+	comment, err := h.store.FindComment(id)
+	if err == database.ErrNotFound || (err == nil && comment.PostID != postID) {
+		return nil, commentNotFound()
+	}
+	if err != nil {
+		return nil, &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	return comment, nil
+}
+
+// commentID parses the commentID route variable of the request
+func commentID(r *http.Request) (int, *httperror.HTTPError) {
+	id, err := strconv.Atoi(mux.Vars(r)["commentID"])
+	if err != nil || id <= 0 {
+		return 0, &httperror.HTTPError{
+			Cause: nil,
+			Type:  httperror.TypeInvalidCommentID,
+			Info: httperror.ErrorMessage{
+				Title:  "Invalid comment id",
+				Detail: "Comment id must be an integer greater than zero",
+			},
+			Code: 400,
+		}
+	}
+
+	return id, nil
+}
+
+// commentNotFound returns the error of a request for a missing comment
+func commentNotFound() *httperror.HTTPError {
+	return &httperror.HTTPError{
+		Cause: nil,
+		Type:  httperror.TypeCommentNotFound,
+		Info: httperror.ErrorMessage{
+			Title:  "Comment not found",
+			Detail: "",
+		},
+		Code: 404,
+	}
+}
@@ -0,0 +1,94 @@
+package httphandlers
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/furkanpala/post-app/internal/core"
+	"github.com/furkanpala/post-app/internal/http/response"
+	"github.com/furkanpala/post-app/internal/validation"
+)
+
+// addComment sends a comment by user and returns the created comment
+func (s *testServer) addComment(path, user, body string) core.Comment {
+	s.t.Helper()
+	w := s.do("POST", path, user, body)
+	expectStatus(s.t, w, 201)
+	var comment core.Comment
+	decode(s.t, w, &comment)
+
+	return comment
+}
+
+func TestGetCommentThreads(t *testing.T) {
+	s := newTestServer(t)
+	path := "/posts/" + strconv.Itoa(s.addPosts("alice", 1)[0]) + "/comments"
+
+	var roots []core.Comment
+	for i := 0; i < CommentsPerPage+1; i++ {
+		roots = append(roots, s.addComment(path, "bob", `{"content":"Comment `+strconv.Itoa(i+1)+`"}`))
+	}
+	reply := s.addComment(path, "alice", `{"content":"Reply","parent_id":`+strconv.Itoa(roots[0].ID)+`}`)
+	s.addComment(path, "bob", `{"content":"Nested","parent_id":`+strconv.Itoa(reply.ID)+`}`)
+
+	w := s.do("GET", path, "", "")
+	expectStatus(t, w, 200)
+	var first response.CommentsResponse
+	decode(t, w, &first)
+	if first.Count != CommentsPerPage || first.Comments[0].ID != roots[0].ID {
+		t.Fatalf("first page has %d threads starting at %d, want %d starting at %d",
+			first.Count, first.Comments[0].ID, CommentsPerPage, roots[0].ID)
+	}
+	replies := first.Comments[0].Replies
+	if len(replies) != 1 || replies[0].ID != reply.ID || len(replies[0].Replies) != 1 {
+		t.Fatalf("first thread has replies %+v, want the reply with its nested reply", replies)
+	}
+
+	w = s.do("GET", path+"?page=2", "", "")
+	expectStatus(t, w, 200)
+	var second response.CommentsResponse
+	decode(t, w, &second)
+	if second.Count != 1 || second.Comments[0].ID != roots[CommentsPerPage].ID {
+		t.Fatalf("second page has %d threads, want the last comment", second.Count)
+	}
+
+	expectStatus(t, s.do("GET", path+"?page=3", "", ""), 404)
+	expectStatus(t, s.do("GET", path+"?page=zero", "", ""), 400)
+}
+
+func TestAddCommentValidation(t *testing.T) {
+	s := newTestServer(t)
+	path := "/posts/" + strconv.Itoa(s.addPosts("alice", 1)[0]) + "/comments"
+
+	expectStatus(t, s.do("POST", path, "bob", `{"content":""}`), 400)
+
+	w := s.do("POST", path, "bob", `{"content":"`+strings.Repeat("a", validation.MaxCommentLength+1)+`"}`)
+	expectStatus(t, w, 400)
+	if !strings.Contains(w.Body.String(), validation.CodeTooLong) {
+		t.Fatalf("too long comment is not reported as %s: %s", validation.CodeTooLong, w.Body.String())
+	}
+
+	deleted := s.addComment(path, "bob", `{"content":"Deleted"}`)
+	expectStatus(t, s.do("DELETE", path+"/"+strconv.Itoa(deleted.ID), "bob", ""), 204)
+	expectStatus(t, s.do("POST", path, "alice", `{"content":"Reply","parent_id":`+strconv.Itoa(deleted.ID)+`}`), 400)
+	expectStatus(t, s.do("POST", path, "alice", `{"content":"Reply","parent_id":999}`), 404)
+}
+
+func TestEditComment(t *testing.T) {
+	s := newTestServer(t)
+	path := "/posts/" + strconv.Itoa(s.addPosts("alice", 1)[0]) + "/comments"
+	comment := s.addComment(path, "bob", `{"content":"Nice"}`)
+	commentPath := path + "/" + strconv.Itoa(comment.ID)
+
+	expectStatus(t, s.do("PATCH", commentPath, "alice", `{"content":"Not nice"}`), 403)
+	expectStatus(t, s.do("DELETE", commentPath, "alice", ""), 403)
+
+	w := s.do("PATCH", commentPath, "bob", `{"content":"Very nice"}`)
+	expectStatus(t, w, 200)
+	var edited core.Comment
+	decode(t, w, &edited)
+	if edited.Content != "Very nice" || edited.EditedAt == 0 {
+		t.Fatalf("edited comment is %+v", edited)
+	}
+}
@@ -0,0 +1,85 @@
+package httphandlers
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/furkanpala/post-app/internal/database"
+	httperror "github.com/furkanpala/post-app/internal/http/error"
+)
+
+// listingState returns the state of the posts selected by query
+func (h *Handler) listingState(query database.PostQuery) (database.ListingState, *httperror.HTTPError) {
+	state, err := h.store.ListingState(query)
+	if err != nil {
+		return state, &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	return state, nil
+}
+
+// notModified sets the validators of a listing of posts in given state
+// and reports whether the request is conditional on them and the client's copy is still fresh,
+// in which case it responses with Not Modified.
+// Listings carrying the reactions of the user are personal
+// and their validators also depend on the authenticated user.
+// Comments and reactions on the posts are part of the validators,
+// as the listing carries their counts.
+func notModified(w http.ResponseWriter, r *http.Request, state database.ListingState, personal bool) bool {
+	key := fmt.Sprintf("%d:%d:%d:%d:%d:%d", state.Count, state.LatestID, state.LatestDate, state.LastModified,
+		state.Comments, state.Reactions)
+	if personal {
+		username := Username(r)
+		key += ":" + username
+		w.Header().Add("Vary", "Authorization")
+	}
+	hash := fnv.New64a()
+	hash.Write([]byte(key))
+	etag := fmt.Sprintf(`W/"%x"`, hash.Sum64())
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if state.LastModified > 0 {
+		w.Header().Set("Last-Modified", time.Unix(state.LastModified, 0).UTC().Format(http.TimeFormat))
+	}
+
+	// If-Modified-Since is only considered when there is no If-None-Match
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		if !weakETagMatches(ifNoneMatch, etag) {
+			return false
+		}
+	} else {
+		since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+		if err != nil || state.LastModified == 0 || state.LastModified > since.Unix() {
+			return false
+		}
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// weakETagMatches reports whether the If-None-Match header value matches etag
+// using the weak comparison
+func weakETagMatches(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
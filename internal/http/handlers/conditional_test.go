@@ -0,0 +1,57 @@
+package httphandlers
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestGetPostsNotModified(t *testing.T) {
+	s := newTestServer(t)
+	ids := s.addPosts("alice", 3)
+
+	w := s.do("GET", "/posts", "", "")
+	expectStatus(t, w, 200)
+	etag := w.Header().Get("ETag")
+	lastModified := w.Header().Get("Last-Modified")
+	if etag == "" || lastModified == "" {
+		t.Fatalf("ETag = %q, Last-Modified = %q, want both set", etag, lastModified)
+	}
+
+	w = s.do("GET", "/posts", "", "", "If-None-Match", etag)
+	expectStatus(t, w, http.StatusNotModified)
+	if w.Body.Len() != 0 {
+		t.Fatalf("Not Modified response has a body: %s", w.Body.String())
+	}
+	expectStatus(t, s.do("GET", "/posts", "", "", "If-Modified-Since", lastModified), http.StatusNotModified)
+
+	// Deleting a post takes it out of the listing, so the listing is modified since then
+	nextSecond()
+	expectStatus(t, s.do("DELETE", "/posts/"+strconv.Itoa(ids[1]), "alice", ""), 204)
+	w = s.do("GET", "/posts", "", "", "If-Modified-Since", lastModified)
+	expectStatus(t, w, 200)
+	if w.Header().Get("Last-Modified") == lastModified {
+		t.Fatalf("Last-Modified %s did not change after a delete", lastModified)
+	}
+	etag = w.Header().Get("ETag")
+
+	// A comment changes the comment count in the listing
+	expectStatus(t, s.do("POST", "/posts/"+strconv.Itoa(ids[0])+"/comments", "bob", `{"content":"Nice"}`), 201)
+	w = s.do("GET", "/posts", "", "", "If-None-Match", etag)
+	expectStatus(t, w, 200)
+	if w.Header().Get("ETag") == etag {
+		t.Fatalf("ETag %s did not change after a comment", etag)
+	}
+
+	// Listings with the reactions of the user depend on the user
+	w = s.do("GET", "/posts", "bob", "", "If-None-Match", w.Header().Get("ETag"))
+	expectStatus(t, w, 200)
+}
+
+// nextSecond waits until the next second starts, so that the changes made after it
+// are later than the ones made before at the one second resolution of Last-Modified
+func nextSecond() {
+	now := time.Now()
+	time.Sleep(now.Truncate(time.Second).Add(time.Second).Sub(now))
+}
@@ -0,0 +1,154 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/furkanpala/post-app/internal/core"
+	"github.com/furkanpala/post-app/internal/database"
+	httperror "github.com/furkanpala/post-app/internal/http/error"
+	"github.com/furkanpala/post-app/internal/http/response"
+	"github.com/furkanpala/post-app/internal/stream"
+)
+
+// DeletePost handles the DELETE requests for /posts/{id} route.
+// Post is not removed but moved into the trash of its sender,
+// where it can be restored from until it is purged.
+func (h *Handler) DeletePost(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	id, httpErr := postID(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	post, httpErr := h.findPost(r, id, false)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	canChange, httpErr := h.canChange(r, post.User)
+	if httpErr != nil {
+		return httpErr
+	}
+	if !canChange {
+		return &httperror.HTTPError{
+			Cause: nil,
+			Type:  httperror.TypeForbidden,
+			Info: httperror.ErrorMessage{
+				Title:  "Forbidden",
+				Detail: "Only the author of the post or an admin can delete it",
+			},
+			Code: 403,
+		}
+	}
+
+	if err := h.store.DeletePost(id); err != nil {
+		if err == database.ErrNotFound {
+			return postNotFound()
+		}
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	if post.Status == core.PostPublished {
+		h.hub.Publish(stream.Event{Type: PostDeletedEvent, Data: deletedPost{ID: id}})
+	}
+	w.WriteHeader(204)
+	return nil
+}
+
+// RestorePost handles the requests for /posts/{id}/restore route.
+// Takes the post out of the trash.
+func (h *Handler) RestorePost(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	id, httpErr := postID(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	post, httpErr := h.findPost(r, id, true)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	canChange, httpErr := h.canChange(r, post.User)
+	if httpErr != nil {
+		return httpErr
+	}
+	if !canChange {
+		return &httperror.HTTPError{
+			Cause: nil,
+			Type:  httperror.TypeForbidden,
+			Info: httperror.ErrorMessage{
+				Title:  "Forbidden",
+				Detail: "Only the author of the post or an admin can restore it",
+			},
+			Code: 403,
+		}
+	}
+
+	if err := h.store.RestorePost(id); err != nil {
+		if err == database.ErrNotFound {
+			return postNotFound()
+		}
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	if post.Status == core.PostPublished {
+		post.DeletedAt = 0
+		h.hub.Publish(stream.Event{Type: PostRestoredEvent, Data: post})
+	}
+	w.WriteHeader(204)
+	return nil
+}
+
+// GetTrash handles the requests for /me/trash route.
+// Returns the deleted posts of the user, most recently deleted first.
+func (h *Handler) GetTrash(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	username := Username(r)
+
+	posts, err := h.store.ListTrash(username)
+	if err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	responseBody := response.PostsResponse{
+		Posts: posts,
+		Count: len(posts),
+	}
+
+	if err := json.NewEncoder(w).Encode(responseBody); err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	return nil
+}
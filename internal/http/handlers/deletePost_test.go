@@ -0,0 +1,58 @@
+package httphandlers
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/furkanpala/post-app/internal/core"
+	"github.com/furkanpala/post-app/internal/http/response"
+)
+
+func TestDeleteAndRestorePost(t *testing.T) {
+	s := newTestServer(t)
+	ids := s.addPosts("alice", 2)
+	path := "/posts/" + strconv.Itoa(ids[0])
+
+	expectStatus(t, s.do("DELETE", path, "bob", ""), 403)
+	expectStatus(t, s.do("DELETE", path, "alice", ""), 204)
+	expectStatus(t, s.do("GET", path, "", ""), 404)
+	expectStatus(t, s.do("DELETE", path, "alice", ""), 404)
+
+	w := s.do("GET", "/posts", "", "")
+	expectStatus(t, w, 200)
+	var listing response.PostsResponse
+	decode(t, w, &listing)
+	if len(listing.Posts) != 1 || listing.Posts[0].ID != ids[1] {
+		t.Fatalf("listing has %d posts, want only post %d", len(listing.Posts), ids[1])
+	}
+
+	w = s.do("GET", "/me/trash", "alice", "")
+	expectStatus(t, w, 200)
+	var trash response.PostsResponse
+	decode(t, w, &trash)
+	if len(trash.Posts) != 1 || trash.Posts[0].ID != ids[0] {
+		t.Fatalf("trash has %d posts, want only post %d", len(trash.Posts), ids[0])
+	}
+
+	expectStatus(t, s.do("POST", path+"/restore", "bob", ""), 403)
+	expectStatus(t, s.do("POST", path+"/restore", "alice", ""), 204)
+	expectStatus(t, s.do("POST", path+"/restore", "alice", ""), 404)
+
+	w = s.do("GET", path, "", "")
+	expectStatus(t, w, 200)
+	var post core.Post
+	decode(t, w, &post)
+	if post.DeletedAt != 0 {
+		t.Fatalf("restored post is deleted at %d", post.DeletedAt)
+	}
+}
+
+func TestAdminDeletesPost(t *testing.T) {
+	s := newTestServer(t)
+	path := "/posts/" + strconv.Itoa(s.addPosts("alice", 1)[0])
+	if err := s.store.AddUser(&core.User{Username: "root", Password: "secret", Admin: true}); err != nil {
+		t.Fatalf("AddUser: %v", err)
+	}
+
+	expectStatus(t, s.do("DELETE", path, "root", ""), 204)
+}
@@ -0,0 +1,47 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	httperror "github.com/furkanpala/post-app/internal/http/error"
+	"github.com/furkanpala/post-app/internal/http/response"
+)
+
+// GetDrafts handles the requests for /me/drafts route.
+// Returns the drafts and scheduled posts of the user, most recently created first.
+func (h *Handler) GetDrafts(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	username := Username(r)
+
+	posts, err := h.store.ListDrafts(username)
+	if err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	responseBody := response.PostsResponse{
+		Posts: posts,
+		Count: len(posts),
+	}
+
+	if err := json.NewEncoder(w).Encode(responseBody); err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/furkanpala/post-app/internal/core"
+	"github.com/furkanpala/post-app/internal/database"
+	httperror "github.com/furkanpala/post-app/internal/http/error"
+	"github.com/furkanpala/post-app/internal/http/request"
+	"github.com/furkanpala/post-app/internal/stream"
+	"github.com/furkanpala/post-app/internal/validation"
+)
+
+// EditPost handles the PATCH requests for /posts/{id} route.
+// Only the sender of the post or an admin can edit it.
+// Drafts and scheduled posts can also be scheduled or published by changing their status.
+// If-Match header must carry the ETag of the post as it was read,
+// so that the changes made by someone else in between are not overwritten.
+// Responses with the edited post and its new ETag.
+func (h *Handler) EditPost(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	id, httpErr := postID(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		return &httperror.HTTPError{
+			Cause: nil,
+			Type:  httperror.TypePreconditionRequired,
+			Info: httperror.ErrorMessage{
+				Title:  "Precondition required",
+				Detail: "If-Match header with the ETag of the post is required",
+			},
+			Code: 428,
+		}
+	}
+
+	var edit request.PostEdit
+	if httpErr := request.DecodeRequestBody(r, &edit); httpErr != nil {
+		return httpErr
+	}
+
+	// Field of the error of an empty edit is empty as it is not about a single field
+	errs := validation.Check("", edit.Title != nil || edit.Content != nil || edit.Status != nil || edit.PublishAt != nil,
+		validation.CodeRequired, "Title, content, status or publishing time required")
+	if edit.Title != nil {
+		errs = append(errs, validation.PostTitle(*edit.Title)...)
+	}
+	if edit.Content != nil {
+		errs = append(errs, validation.PostContent(*edit.Content)...)
+	}
+	if errs != nil {
+		return httperror.Invalid(httperror.TypeInvalidPost, "Invalid post info", errs)
+	}
+
+	post, httpErr := h.findPost(r, id, false)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	canChange, httpErr := h.canChange(r, post.User)
+	if httpErr != nil {
+		return httpErr
+	}
+	if !canChange {
+		return &httperror.HTTPError{
+			Cause: nil,
+			Type:  httperror.TypeForbidden,
+			Info: httperror.ErrorMessage{
+				Title:  "Forbidden",
+				Detail: "Only the author of the post or an admin can edit it",
+			},
+			Code: 403,
+		}
+	}
+
+	if !etagMatches(ifMatch, postETag(post)) {
+		return preconditionFailed()
+	}
+	wasPublished := post.Status == core.PostPublished
+
+	if edit.Status != nil || edit.PublishAt != nil {
+		if post.Status == core.PostPublished {
+			return httperror.Invalid(httperror.TypeInvalidPost, "Invalid post info", validation.Errors{
+				{Field: "status", Code: validation.CodeInvalid, Message: "Status of a published post cannot be changed"},
+			})
+		}
+
+		if edit.Status != nil {
+			post.Status = *edit.Status
+			post.PublishAt = 0
+		}
+		if edit.PublishAt != nil {
+			post.PublishAt = *edit.PublishAt
+		}
+
+		if errs := validation.Status(post.Status, post.PublishAt); errs != nil {
+			return httperror.Invalid(httperror.TypeInvalidPost, "Invalid post info", errs)
+		}
+	}
+
+	if edit.Title != nil {
+		post.Title = *edit.Title
+	}
+	if edit.Content != nil {
+		post.Content = *edit.Content
+	}
+
+	// Post may have been edited after it was read above
+	if err := h.store.UpdatePost(post, post.Version); err != nil {
+		if err == database.ErrVersionConflict {
+			return preconditionFailed()
+		}
+		if err == database.ErrNotFound {
+			return postNotFound()
+		}
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	if wasPublished {
+		h.hub.Publish(stream.Event{Type: PostEditedEvent, Data: post})
+	} else if post.Status == core.PostPublished {
+		h.hub.Publish(stream.Event{ID: database.CursorOf(*post).String(), Type: PostPublishedEvent, Data: post})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", postETag(post))
+	if err := json.NewEncoder(w).Encode(post); err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	return nil
+}
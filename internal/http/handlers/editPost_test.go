@@ -0,0 +1,47 @@
+package httphandlers
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/furkanpala/post-app/internal/core"
+)
+
+func TestEditPostIfMatch(t *testing.T) {
+	s := newTestServer(t)
+	path := "/posts/" + strconv.Itoa(s.addPosts("alice", 1)[0])
+	edit := `{"title":"Edited"}`
+
+	w := s.do("GET", path, "", "")
+	expectStatus(t, w, 200)
+	etag := w.Header().Get("ETag")
+
+	expectStatus(t, s.do("PATCH", path, "alice", edit), 428)
+	expectStatus(t, s.do("PATCH", path, "alice", edit, "If-Match", `"41"`), 412)
+	expectStatus(t, s.do("PATCH", path, "bob", edit, "If-Match", etag), 403)
+
+	w = s.do("PATCH", path, "alice", edit, "If-Match", etag)
+	expectStatus(t, w, 200)
+	var post core.Post
+	decode(t, w, &post)
+	if post.Title != "Edited" || post.Version != 2 {
+		t.Fatalf("edited post has title %q and version %d, want %q and 2", post.Title, post.Version, "Edited")
+	}
+	if w.Header().Get("ETag") == etag {
+		t.Fatalf("ETag %s did not change after the edit", etag)
+	}
+
+	// The edit above made the first ETag stale
+	expectStatus(t, s.do("PATCH", path, "alice", `{"title":"Lost"}`, "If-Match", etag), 412)
+	expectStatus(t, s.do("PATCH", path, "alice", `{"title":"Forced"}`, "If-Match", "*"), 200)
+}
+
+func TestAdminEditsPost(t *testing.T) {
+	s := newTestServer(t)
+	path := "/posts/" + strconv.Itoa(s.addPosts("alice", 1)[0])
+	if err := s.store.AddUser(&core.User{Username: "root", Password: "secret", Admin: true}); err != nil {
+		t.Fatalf("AddUser: %v", err)
+	}
+
+	expectStatus(t, s.do("PATCH", path, "root", `{"title":"Moderated"}`, "If-Match", "*"), 200)
+}
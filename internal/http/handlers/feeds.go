@@ -0,0 +1,109 @@
+package httphandlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/furkanpala/post-app/internal/database"
+	"github.com/furkanpala/post-app/internal/env"
+	"github.com/furkanpala/post-app/internal/feed"
+	httperror "github.com/furkanpala/post-app/internal/http/error"
+	"github.com/gorilla/mux"
+)
+
+// FeedSize is the number of latest posts in a feed
+const FeedSize = 20
+
+// FeedTagURI is the base of the ids in feeds when PUBLIC_URL is not set.
+// Ids must not depend on the host a feed is requested from, or readers would show posts twice.
+const FeedTagURI = "tag:post-app,2021:"
+
+// GetRSSFeed handles the GET requests for /feed.rss route.
+// Returns the latest posts as an RSS 2.0 feed.
+func (h *Handler) GetRSSFeed(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	return h.writeFeed(w, r, "Post App", database.PostQuery{}, feed.RSS, "application/rss+xml")
+}
+
+// GetAtomFeed handles the GET requests for /feed.atom route.
+// Returns the latest posts as an Atom feed.
+func (h *Handler) GetAtomFeed(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	return h.writeFeed(w, r, "Post App", database.PostQuery{}, feed.Atom, "application/atom+xml")
+}
+
+// GetUserAtomFeed handles the GET requests for /users/{username}/feed.atom route.
+// Returns the latest posts of the user as an Atom feed.
+func (h *Handler) GetUserAtomFeed(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	user, httpErr := h.findUser(mux.Vars(r)["username"])
+	if httpErr != nil {
+		return httpErr
+	}
+
+	return h.writeFeed(w, r, "Posts of "+user.Username, database.PostQuery{Author: user.Username},
+		feed.Atom, "application/atom+xml")
+}
+
+// writeFeed responses with the latest posts selected by query rendered by render
+func (h *Handler) writeFeed(w http.ResponseWriter, r *http.Request, title string, query database.PostQuery,
+	render func(feed.Feed) ([]byte, error), contentType string) *httperror.HTTPError {
+	query.Limit = FeedSize
+	posts, err := h.store.ListPosts(query)
+	if err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	body, err := render(feed.Feed{
+		Title:   title,
+		BaseURL: baseURL(r),
+		IDBase:  feedIDBase(),
+		Path:    r.URL.EscapedPath(),
+		Posts:   posts,
+	})
+	if err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	w.Header().Set("Content-Type", contentType+"; charset=utf-8")
+	w.Write(body)
+
+	return nil
+}
+
+// feedIDBase returns the base of the ids in feeds, PUBLIC_URL if it is set
+func feedIDBase() string {
+	if env.PublicURL != "" {
+		return strings.TrimSuffix(env.PublicURL, "/")
+	}
+
+	return FeedTagURI
+}
+
+// baseURL returns the absolute URL of the server without a trailing slash.
+// PUBLIC_URL is used if it is set, otherwise it is guessed from the request.
+func baseURL(r *http.Request) string {
+	if env.PublicURL != "" {
+		return strings.TrimSuffix(env.PublicURL, "/")
+	}
+
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,121 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/furkanpala/post-app/internal/database"
+	httperror "github.com/furkanpala/post-app/internal/http/error"
+	"github.com/furkanpala/post-app/internal/http/response"
+	"github.com/gorilla/mux"
+)
+
+// FollowUser handles the PUT requests for /users/{username}/follow route.
+// Following a user again changes nothing.
+func (h *Handler) FollowUser(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	return h.changeFollow(w, r, h.store.Follow)
+}
+
+// UnfollowUser handles the DELETE requests for /users/{username}/follow route.
+func (h *Handler) UnfollowUser(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	return h.changeFollow(w, r, h.store.Unfollow)
+}
+
+// changeFollow applies change to the following of the user of the route by the authenticated user
+func (h *Handler) changeFollow(w http.ResponseWriter, r *http.Request,
+	change func(follower, followee string) error) *httperror.HTTPError {
+	followee, httpErr := h.findUser(mux.Vars(r)["username"])
+	if httpErr != nil {
+		return httpErr
+	}
+
+	follower := Username(r)
+	if follower == followee.Username {
+		return &httperror.HTTPError{
+			Cause: nil,
+			Type:  httperror.TypeInvalidFollow,
+			Info: httperror.ErrorMessage{
+				Title:  "Invalid follow",
+				Detail: "Users cannot follow themselves",
+			},
+			Code: 400,
+		}
+	}
+
+	if err := change(follower, followee.Username); err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	w.WriteHeader(204)
+	return nil
+}
+
+// GetFollowers handles the GET requests for /users/{username}/followers route.
+// Returns the users following the user, most recent first.
+func (h *Handler) GetFollowers(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	return h.listUsers(w, r, h.store.ListFollowers)
+}
+
+// GetFollowing handles the GET requests for /users/{username}/following route.
+// Returns the users followed by the user, most recent first.
+func (h *Handler) GetFollowing(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	return h.listUsers(w, r, h.store.ListFollowing)
+}
+
+// listUsers responses with the users list returns for the user of the route
+func (h *Handler) listUsers(w http.ResponseWriter, r *http.Request,
+	list func(username string) ([]string, error)) *httperror.HTTPError {
+	user, httpErr := h.findUser(mux.Vars(r)["username"])
+	if httpErr != nil {
+		return httpErr
+	}
+
+	users, err := list(user.Username)
+	if err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	responseBody := response.UsersResponse{
+		Users: users,
+		Count: len(users),
+	}
+
+	if err := json.NewEncoder(w).Encode(responseBody); err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	return nil
+}
+
+// GetTimeline handles the GET requests for /timeline route.
+// Returns the posts of the users followed by the authenticated user, newest first.
+// Pages are selected with cursor query parameter the same way as /posts?cursor=.
+func (h *Handler) GetTimeline(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	query := database.PostQuery{FollowedBy: Username(r)}
+
+	return h.getPostsAfterCursor(w, r, r.URL.Query().Get("cursor"), query)
+}
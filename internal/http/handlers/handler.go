@@ -0,0 +1,43 @@
+package httphandlers
+
+import (
+	"strings"
+
+	"github.com/furkanpala/post-app/internal/core"
+	"github.com/furkanpala/post-app/internal/database"
+	"github.com/furkanpala/post-app/internal/storage"
+	"github.com/furkanpala/post-app/internal/stream"
+)
+
+// Handler holds the dependencies of the route handlers.
+// Every route handler is a method of Handler so that
+// the server can be run against any database.Store.
+type Handler struct {
+	store database.Store
+	// blobs keeps the files of attachments
+	blobs storage.BlobStore
+	// reactionTypes are the types of reaction users can give to posts
+	reactionTypes map[string]bool
+	// hub delivers the changes of posts to the clients of /posts/stream
+	hub *stream.Hub
+}
+
+// NewHandler returns a Handler which uses the given stores
+// and allows the default reaction types
+func NewHandler(store database.Store, blobs storage.BlobStore) *Handler {
+	h := &Handler{store: store, blobs: blobs, hub: stream.NewHub()}
+	h.SetReactionTypes(core.DefaultReactionTypes)
+
+	return h
+}
+
+// SetReactionTypes sets the types of reaction users can give to posts.
+// Types are trimmed and lower cased, empty ones are skipped.
+func (h *Handler) SetReactionTypes(types []string) {
+	h.reactionTypes = make(map[string]bool, len(types))
+	for _, t := range types {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			h.reactionTypes[t] = true
+		}
+	}
+}
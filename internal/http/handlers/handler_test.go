@@ -0,0 +1,121 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/furkanpala/post-app/internal/core"
+	"github.com/furkanpala/post-app/internal/database"
+	"github.com/furkanpala/post-app/internal/storage"
+	"github.com/gorilla/mux"
+)
+
+// testUserHeader names the user a test request is sent by, in place of a bearer access token
+const testUserHeader = "X-Test-User"
+
+// testServer is a Handler on a MemoryStore behind the routes of the tested handlers
+type testServer struct {
+	t       *testing.T
+	store   *database.MemoryStore
+	handler *Handler
+	router  *mux.Router
+}
+
+func newTestServer(t *testing.T) *testServer {
+	dir, err := ioutil.TempDir("", "post-app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	blobs, err := storage.NewLocalBlobStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := database.NewMemoryStore()
+	h := NewHandler(store, blobs)
+
+	router := mux.NewRouter()
+	route := func(path string, handler RouteHandler, method string) {
+		router.Handle(path, withTestUser(handler)).Methods(method)
+	}
+	route("/posts", h.GetPosts, "GET")
+	route("/posts", h.AddPost, "POST")
+	route("/posts/search", h.SearchPosts, "GET")
+	route("/posts/stream", h.StreamPosts, "GET")
+	router.Handle("/posts/{page:[0-9]+}", withTestUser(RouteHandler(h.GetLegacyPage))).Queries("as", "page").Methods("GET")
+	route("/posts/{id:[0-9]+}", h.GetPost, "GET")
+	route("/posts/{id:[0-9]+}", h.EditPost, "PATCH")
+	route("/posts/{id:[0-9]+}", h.DeletePost, "DELETE")
+	route("/posts/{id:[0-9]+}/restore", h.RestorePost, "POST")
+	route("/posts/{id:[0-9]+}/comments", h.GetComments, "GET")
+	route("/posts/{id:[0-9]+}/comments", h.AddComment, "POST")
+	route("/posts/{id:[0-9]+}/comments/{commentID:[0-9]+}", h.EditComment, "PATCH")
+	route("/posts/{id:[0-9]+}/comments/{commentID:[0-9]+}", h.DeleteComment, "DELETE")
+	route("/me/trash", h.GetTrash, "GET")
+	route("/attachments", h.UploadAttachment, "POST")
+
+	return &testServer{t: t, store: store, handler: h, router: router}
+}
+
+// withTestUser puts the user of testUserHeader into the request context like the auth middleware
+func withTestUser(next RouteHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username := r.Header.Get(testUserHeader); username != "" {
+			r = WithUsername(r, username)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// do sends a request by user, anonymous if user is empty, with the headers given as name, value pairs
+func (s *testServer) do(method, path, user, body string, headers ...string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	if user != "" {
+		r.Header.Set(testUserHeader, user)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		r.Header.Set(headers[i], headers[i+1])
+	}
+
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, r)
+
+	return w
+}
+
+// addPosts adds n published posts of user into the store and returns their ids, oldest first
+func (s *testServer) addPosts(user string, n int) []int {
+	ids := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		post := core.Post{Title: "Post " + strconv.Itoa(i+1), Content: "Content", User: user, Status: core.PostPublished}
+		if err := s.store.AddPost(&post); err != nil {
+			s.t.Fatalf("AddPost: %v", err)
+		}
+		ids = append(ids, post.ID)
+	}
+
+	return ids
+}
+
+// expectStatus fails the test if the response does not have the status code
+func expectStatus(t *testing.T, w *httptest.ResponseRecorder, code int) {
+	t.Helper()
+	if w.Code != code {
+		t.Fatalf("status = %d, want %d, body: %s", w.Code, code, w.Body.String())
+	}
+}
+
+// decode decodes the JSON body of the response into v
+func decode(t *testing.T, w *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if err := json.NewDecoder(w.Body).Decode(v); err != nil {
+		t.Fatalf("decoding %q: %v", w.Body.String(), err)
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/furkanpala/post-app/internal/core"
-	"github.com/furkanpala/post-app/internal/database"
 	"github.com/furkanpala/post-app/internal/env"
 	httperror "github.com/furkanpala/post-app/internal/http/error"
 	"github.com/furkanpala/post-app/internal/http/request"
@@ -17,7 +16,7 @@ import (
 // HandleLogin function handles the request for /login route.
 // Check credentials against database.
 // If correct, responses with an access token and a refresh token
-func HandleLogin(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+func (h *Handler) HandleLogin(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
 	var user core.User
 
 	// Parse request body
@@ -26,7 +25,7 @@ func HandleLogin(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
 	}
 
 	// Check credentials
-	if err := validateUser(&user); err != nil {
+	if err := h.validateUser(&user); err != nil {
 		return err
 	}
 
@@ -35,6 +34,7 @@ func HandleLogin(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
 	if err != nil {
 		return &httperror.HTTPError{
 			Cause: err,
+			Type:  httperror.TypeInternal,
 			Info: httperror.ErrorMessage{
 				Title:  "Internal server error",
 				Detail: "",
@@ -48,6 +48,7 @@ func HandleLogin(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
 	if err != nil {
 		return &httperror.HTTPError{
 			Cause: err,
+			Type:  httperror.TypeInternal,
 			Info: httperror.ErrorMessage{
 				Title:  "Internal server error",
 				Detail: "",
@@ -82,12 +83,13 @@ func HandleLogin(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
 }
 
 // validateUser function checks if user's credentials are valid for log in
-func validateUser(user *core.User) *httperror.HTTPError {
+func (h *Handler) validateUser(user *core.User) *httperror.HTTPError {
 	// Check if user exists in database
-	dbUser, err := database.FindUser(user.Username)
+	dbUser, err := h.store.FindUser(user.Username)
 	if err != nil {
 		return &httperror.HTTPError{
 			Cause: err,
+			Type:  httperror.TypeInternal,
 			Info: httperror.ErrorMessage{
 				Title:  "Internal server error",
 				Detail: "",
@@ -99,6 +101,7 @@ func validateUser(user *core.User) *httperror.HTTPError {
 	if dbUser == nil {
 		return &httperror.HTTPError{
 			Cause: nil,
+			Type:  httperror.TypeInvalidCredentials,
 			Info: httperror.ErrorMessage{
 				Title:  "Unauthorized",
 				Detail: "Invalid credentials",
@@ -111,6 +114,7 @@ func validateUser(user *core.User) *httperror.HTTPError {
 	if err := dbUser.Compare(user.Password); err != nil {
 		return &httperror.HTTPError{
 			Cause: nil,
+			Type:  httperror.TypeInvalidCredentials,
 			Info: httperror.ErrorMessage{
 				Title:  "Unauthorized",
 				Detail: "Invalid credentials",
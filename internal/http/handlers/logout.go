@@ -2,9 +2,9 @@ package httphandlers
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
-	"github.com/furkanpala/post-app/internal/database"
 	"github.com/furkanpala/post-app/internal/env"
 	httperror "github.com/furkanpala/post-app/internal/http/error"
 	jwttoken "github.com/furkanpala/post-app/internal/http/token"
@@ -12,9 +12,10 @@ import (
 
 // HandleLogout function handles the requests to /token/logout route.
 // If given refresh token is valid, then user is logged out.
-// Refresh token's jti is added to the blacklist in database.
+// Refresh token's jti is added to the blacklist in database,
+// so is the jti of the bearer access token if the request has a valid one.
 // Responses with empty refresh token.
-func HandleLogout(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+func (h *Handler) HandleLogout(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
 	// Parse cookie
 	cookie, httpErr := jwttoken.ParseCookie(r, "jid")
 	if httpErr != nil {
@@ -30,10 +31,11 @@ func HandleLogout(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
 	}
 
 	// Check if given token's jti is already in blacklist
-	isInBlacklist, err := database.FindJTI(claims.Id)
+	isInBlacklist, err := h.store.FindJTI(claims.Id)
 	if err != nil {
 		return &httperror.HTTPError{
 			Cause: err,
+			Type:  httperror.TypeInternal,
 			Info: httperror.ErrorMessage{
 				Title:  "Internal server error",
 				Detail: "",
@@ -45,6 +47,7 @@ func HandleLogout(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
 	if isInBlacklist {
 		return &httperror.HTTPError{
 			Cause: nil,
+			Type:  httperror.TypeRevokedToken,
 			Info: httperror.ErrorMessage{
 				Title:  "Unauthorized",
 				Detail: "Invalid credentials",
@@ -54,9 +57,10 @@ func HandleLogout(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
 	}
 
 	// Blacklist the token through adding it's "jti" into database
-	if err := database.BlacklistToken(claims.Id, claims.ExpiresAt); err != nil {
+	if err := h.store.BlacklistToken(claims.Id, claims.ExpiresAt); err != nil {
 		return &httperror.HTTPError{
 			Cause: err,
+			Type:  httperror.TypeInternal,
 			Info: httperror.ErrorMessage{
 				Title:  "Internal server error",
 				Detail: "",
@@ -64,6 +68,25 @@ func HandleLogout(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
 			Code: 500,
 		}
 	}
+
+	// Blacklist the access token too, so that it can not be used until it expires
+	if authorization := strings.Split(r.Header.Get("Authorization"), " "); len(authorization) == 2 {
+		var accessClaims jwttoken.Claims
+		if _, httpErr := jwttoken.VerifyToken(authorization[1], env.AccessTokenSecret, &accessClaims); httpErr == nil {
+			if err := h.store.BlacklistToken(accessClaims.Id, accessClaims.ExpiresAt); err != nil {
+				return &httperror.HTTPError{
+					Cause: err,
+					Type:  httperror.TypeInternal,
+					Info: httperror.ErrorMessage{
+						Title:  "Internal server error",
+						Detail: "",
+					},
+					Code: 500,
+				}
+			}
+		}
+	}
+
 	newCookie := http.Cookie{
 		Name:     "jid",
 		Value:    "",
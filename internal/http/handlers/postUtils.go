@@ -0,0 +1,185 @@
+package httphandlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/furkanpala/post-app/internal/core"
+	"github.com/furkanpala/post-app/internal/database"
+	httperror "github.com/furkanpala/post-app/internal/http/error"
+	"github.com/furkanpala/post-app/internal/validation"
+	"github.com/gorilla/mux"
+)
+
+// postID parses the id route variable of the request
+func postID(r *http.Request) (int, *httperror.HTTPError) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		return 0, &httperror.HTTPError{
+			Cause: nil,
+			Type:  httperror.TypeInvalidPostID,
+			Info: httperror.ErrorMessage{
+				Title:  "Invalid post id",
+				Detail: "Post id must be an integer greater than zero",
+			},
+			Code: 400,
+		}
+	}
+
+	return id, nil
+}
+
+// postETag returns the entity tag of post which changes on every edit
+func postETag(post *core.Post) string {
+	return `"` + strconv.Itoa(post.Version) + `"`
+}
+
+// etagMatches reports whether the If-Match header value matches etag
+func etagMatches(ifMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// preconditionFailed returns the error of a write based on a stale version of a post
+func preconditionFailed() *httperror.HTTPError {
+	return &httperror.HTTPError{
+		Cause: nil,
+		Type:  httperror.TypePreconditionFailed,
+		Info: httperror.ErrorMessage{
+			Title:  "Precondition failed",
+			Detail: "Post has been changed since it was read",
+		},
+		Code: 412,
+	}
+}
+
+// findPost returns the post with given id.
+// If deleted is true the post must be in the trash, otherwise it must not be.
+// Drafts and scheduled posts are found only by the users who can change them.
+// Responses with Not Found error if there is no such post.
+func (h *Handler) findPost(r *http.Request, id int, deleted bool) (*core.Post, *httperror.HTTPError) {
+	post, err := h.store.FindPost(id)
+	if err == database.ErrNotFound || (err == nil && (post.DeletedAt != 0) != deleted) {
+		return nil, postNotFound()
+	}
+	if err == nil && post.Status != core.PostPublished {
+		canChange, httpErr := h.canChange(r, post.User)
+		if httpErr != nil {
+			return nil, httpErr
+		}
+		if !canChange {
+			return nil, postNotFound()
+		}
+	}
+	if err != nil {
+		return nil, &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	return post, nil
+}
+
+// postNotFound returns the error of a request for a missing post
+func postNotFound() *httperror.HTTPError {
+	return &httperror.HTTPError{
+		Cause: nil,
+		Type:  httperror.TypePostNotFound,
+		Info: httperror.ErrorMessage{
+			Title:  "Post not found",
+			Detail: "",
+		},
+		Code: 404,
+	}
+}
+
+// queryTags returns the normalized tag query parameters of the request
+func queryTags(r *http.Request) ([]string, *httperror.HTTPError) {
+	tags, err := core.NormalizeTagFilters(r.URL.Query()["tag"])
+	if err != nil {
+		return nil, &httperror.HTTPError{
+			Cause: nil,
+			Type:  httperror.TypeInvalidTag,
+			Info: httperror.ErrorMessage{
+				Title:  "Invalid tag",
+				Detail: err.Error(),
+			},
+			Code: 400,
+		}
+	}
+
+	return tags, nil
+}
+
+// queryPosts returns the query of the posts listing selected by the query parameters of the request:
+// tag, author, since and until, unix times or RFC 3339 dates, title, matched as a substring,
+// and sort, one of newest, oldest and most_commented.
+func queryPosts(r *http.Request) (database.PostQuery, *httperror.HTTPError) {
+	tags, httpErr := queryTags(r)
+	if httpErr != nil {
+		return database.PostQuery{}, httpErr
+	}
+
+	params := r.URL.Query()
+	query := database.PostQuery{
+		Author: strings.TrimSpace(params.Get("author")),
+		Title:  strings.TrimSpace(params.Get("title")),
+		Tags:   tags,
+	}
+
+	var errs validation.Errors
+	var err error
+	if since := params.Get("since"); since != "" {
+		query.Since, err = parseTime(since)
+		errs = append(errs, validation.Check("since", err == nil, validation.CodeInvalid,
+			"Since must be a unix time or an RFC 3339 date")...)
+	}
+	if until := params.Get("until"); until != "" {
+		query.Until, err = parseTime(until)
+		errs = append(errs, validation.Check("until", err == nil, validation.CodeInvalid,
+			"Until must be a unix time or an RFC 3339 date")...)
+	}
+	if query.Since != 0 && query.Until != 0 {
+		errs = append(errs, validation.Check("until", query.Since < query.Until, validation.CodeInvalid,
+			"Since must be before until")...)
+	}
+
+	if query.Sort = params.Get("sort"); query.Sort != "" {
+		errs = append(errs, validation.String("sort", query.Sort,
+			validation.OneOf("Sort", database.SortNewest, database.SortOldest, database.SortMostCommented))...)
+	}
+
+	if errs != nil {
+		return query, httperror.Invalid(httperror.TypeInvalidFilter, "Invalid filter", errs)
+	}
+
+	return query, nil
+}
+
+// parseTime parses a unix time or an RFC 3339 date into a unix time
+func parseTime(value string) (int64, error) {
+	if unix, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return unix, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, err
+	}
+
+	return t.Unix(), nil
+}
@@ -10,25 +10,74 @@ import (
 	httperror "github.com/furkanpala/post-app/internal/http/error"
 	"github.com/furkanpala/post-app/internal/http/request"
 	"github.com/furkanpala/post-app/internal/http/response"
-	"github.com/gorilla/context"
+	"github.com/furkanpala/post-app/internal/stream"
+	"github.com/furkanpala/post-app/internal/validation"
 	"github.com/gorilla/mux"
 )
 
 const PostsPerPage = 6
 
-func GetPosts(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
-	posts, err := database.GetAllPosts()
+// GetPosts returns all the posts.
+// If page query parameter is given, returns the posts on that page instead.
+// If cursor query parameter is given, returns the page of posts
+// which comes after the cursor instead. Empty cursor means the first page.
+// Posts can be filtered with tag query parameters, e.g. ?tag=go&tag=sqlite
+// lists only the posts tagged with both, and with the other parameters of queryPosts,
+// e.g. ?author=alice&since=2021-03-01T00:00:00Z&until=2021-03-08T00:00:00Z&sort=oldest.
+// Cursors only page the posts sorted newest first.
+// Posts come with their reaction counts and, if the request is authenticated,
+// the reactions of the user. The same holds for every listing of posts.
+// Listings carry a weak ETag and Last-Modified and are answered with Not Modified
+// when the posts have not changed since the client's copy.
+func (h *Handler) GetPosts(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	if _, ok := r.URL.Query()["page"]; ok {
+		return h.GetPostsOnPage(w, r)
+	}
+	query, httpErr := queryPosts(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if cursor, ok := r.URL.Query()["cursor"]; ok {
+		if query.Sort != "" && query.Sort != database.SortNewest {
+			return &httperror.HTTPError{
+				Cause: nil,
+				Type:  httperror.TypeInvalidFilter,
+				Info: httperror.ErrorMessage{
+					Title:  "Invalid filter",
+					Detail: "Cursor can only be used with newest sort",
+				},
+				Code: 400,
+			}
+		}
+		return h.getPostsAfterCursor(w, r, cursor[0], query)
+	}
+
+	state, httpErr := h.listingState(query)
+	if httpErr != nil {
+		return httpErr
+	}
+	if notModified(w, r, state, true) {
+		return nil
+	}
+
+	posts, err := h.store.ListPosts(query)
 	if err != nil {
 		return &httperror.HTTPError{
 			Cause: err,
+			Type:  httperror.TypeInternal,
 			Info: httperror.ErrorMessage{
 				Title:  "Internal server error",
-				Detail: err.Error(),
+				Detail: "",
 			},
 			Code: 500,
 		}
 	}
 
+	if httpErr := h.loadReactions(r, posts); httpErr != nil {
+		return httpErr
+	}
+
 	responseBody := response.PostsResponse{
 		Posts: posts,
 		Count: len(posts),
@@ -37,9 +86,10 @@ func GetPosts(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
 	if err := json.NewEncoder(w).Encode(responseBody); err != nil {
 		return &httperror.HTTPError{
 			Cause: err,
+			Type:  httperror.TypeInternal,
 			Info: httperror.ErrorMessage{
 				Title:  "Internal server error",
-				Detail: err.Error(),
+				Detail: "",
 			},
 			Code: 500,
 		}
@@ -49,15 +99,51 @@ func GetPosts(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
 }
 
 // GetPostsOnPage returns a slice of posts which are on a specific page.
-func GetPostsOnPage(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
-	params := mux.Vars(r)
-	page, err := strconv.Atoi(params["page"])
+// Page is read from the page route variable of /posts/page/{page}
+// or from the page query parameter of /posts.
+func (h *Handler) GetPostsOnPage(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	pageParam, ok := mux.Vars(r)["page"]
+	if !ok {
+		pageParam = r.URL.Query().Get("page")
+	}
+
+	query, httpErr := queryPosts(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	return h.getPostsOnPage(w, r, pageParam, query)
+}
+
+// GetLegacyPage handles the requests for /posts/{page}?as=page route,
+// which lists pages like /posts/{page} did before the route started addressing single posts.
+// Responses point at the route which replaces it.
+func (h *Handler) GetLegacyPage(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	page := mux.Vars(r)["page"]
+
+	query, httpErr := queryPosts(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	w.Header().Set("Deprecation", "true")
+	w.Header().Set("Link", `</posts?page=`+page+`>; rel="successor-version"`)
+
+	return h.getPostsOnPage(w, r, page, query)
+}
+
+// getPostsOnPage returns the page of the posts selected by query.
+// Responses with Not Found error if there are no posts on the page.
+func (h *Handler) getPostsOnPage(w http.ResponseWriter, r *http.Request, pageParam string,
+	query database.PostQuery) *httperror.HTTPError {
+	page, err := strconv.Atoi(pageParam)
 	if err != nil {
 		return &httperror.HTTPError{
 			Cause: err,
+			Type:  httperror.TypeInvalidPage,
 			Info: httperror.ErrorMessage{
 				Title:  "Invalid page",
-				Detail: err.Error(),
+				Detail: "Page must be an integer greater than zero",
 			},
 			Code: 400,
 		}
@@ -66,6 +152,7 @@ func GetPostsOnPage(w http.ResponseWriter, r *http.Request) *httperror.HTTPError
 	if page <= 0 {
 		return &httperror.HTTPError{
 			Cause: nil,
+			Type:  httperror.TypePageNotFound,
 			Info: httperror.ErrorMessage{
 				Title:  "Page not found",
 				Detail: "Page must be an integer greater than zero",
@@ -74,22 +161,17 @@ func GetPostsOnPage(w http.ResponseWriter, r *http.Request) *httperror.HTTPError
 		}
 	}
 
-	postsCount, err := database.CountPosts()
-	if err != nil {
-		return &httperror.HTTPError{
-			Cause: err,
-			Info: httperror.ErrorMessage{
-				Title:  "Internal server error",
-				Detail: err.Error(),
-			},
-			Code: 500,
-		}
+	state, httpErr := h.listingState(query)
+	if httpErr != nil {
+		return httpErr
 	}
+	postsCount := state.Count
 
 	firstPostIndex := PostsPerPage * (page - 1)
 	if firstPostIndex+1 > postsCount {
 		return &httperror.HTTPError{
 			Cause: nil,
+			Type:  httperror.TypePageNotFound,
 			Info: httperror.ErrorMessage{
 				Title:  "Page not found",
 				Detail: "",
@@ -98,36 +180,44 @@ func GetPostsOnPage(w http.ResponseWriter, r *http.Request) *httperror.HTTPError
 		}
 	}
 
-	lastPostIndex := PostsPerPage*page - 1
-
-	if lastPostIndex+1 > postsCount {
-		lastPostIndex = postsCount - 1
+	if notModified(w, r, state, true) {
+		return nil
 	}
 
-	posts, err := database.GetAllPosts()
+	query.Limit = PostsPerPage
+	query.Offset = firstPostIndex
+	posts, err := h.store.ListPosts(query)
 	if err != nil {
 		return &httperror.HTTPError{
 			Cause: err,
+			Type:  httperror.TypeInternal,
 			Info: httperror.ErrorMessage{
 				Title:  "Internal server error",
-				Detail: err.Error(),
+				Detail: "",
 			},
 			Code: 500,
 		}
 	}
-	posts = posts[firstPostIndex : lastPostIndex+1]
+
+	if httpErr := h.loadReactions(r, posts); httpErr != nil {
+		return httpErr
+	}
 
 	responseBody := response.PostsResponse{
 		Posts: posts,
 		Count: len(posts),
 	}
+	if len(posts) > 0 && firstPostIndex+len(posts) < postsCount && (query.Sort == "" || query.Sort == database.SortNewest) {
+		responseBody.NextCursor = database.CursorOf(posts[len(posts)-1]).String()
+	}
 
 	if err := json.NewEncoder(w).Encode(responseBody); err != nil {
 		return &httperror.HTTPError{
 			Cause: err,
+			Type:  httperror.TypeInternal,
 			Info: httperror.ErrorMessage{
 				Title:  "Internal server error",
-				Detail: err.Error(),
+				Detail: "",
 			},
 			Code: 500,
 		}
@@ -136,76 +226,183 @@ func GetPostsOnPage(w http.ResponseWriter, r *http.Request) *httperror.HTTPError
 	return nil
 }
 
-func AddPost(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
-	var post core.Post
-	if httpErr := request.DecodeRequestBody(r, &post); httpErr != nil {
+// getPostsAfterCursor returns a page of the posts selected by query which come after the given cursor.
+// Unlike page numbers, cursors stay stable when new posts are added between requests.
+func (h *Handler) getPostsAfterCursor(w http.ResponseWriter, r *http.Request, cursorString string,
+	query database.PostQuery) *httperror.HTTPError {
+	query.Limit = PostsPerPage + 1
+
+	if cursorString != "" {
+		cursor, err := database.ParseCursor(cursorString)
+		if err != nil {
+			return &httperror.HTTPError{
+				Cause: nil,
+				Type:  httperror.TypeInvalidCursor,
+				Info: httperror.ErrorMessage{
+					Title:  "Invalid cursor",
+					Detail: err.Error(),
+				},
+				Code: 400,
+			}
+		}
+		query.After = &cursor
+	}
+
+	state, httpErr := h.listingState(query)
+	if httpErr != nil {
 		return httpErr
 	}
+	if notModified(w, r, state, true) {
+		return nil
+	}
 
-	username := context.Get(r, "username")
-	post.User = username.(string)
+	// One more post than a page is fetched to know if there is a next page
+	posts, err := h.store.ListPosts(query)
+	if err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
 
-	errorMessage := ""
-	if len(post.Title) == 0 {
-		errorMessage += "Title required"
+	responseBody := response.PostsResponse{}
+	if len(posts) > PostsPerPage {
+		posts = posts[:PostsPerPage]
+		responseBody.NextCursor = database.CursorOf(posts[len(posts)-1]).String()
 	}
-	if len(post.Content) == 0 {
-		if errorMessage != "" {
-			errorMessage += "|"
-		}
-		errorMessage += "Content required"
+
+	if httpErr := h.loadReactions(r, posts); httpErr != nil {
+		return httpErr
 	}
+	responseBody.Posts = posts
+	responseBody.Count = len(posts)
 
-	if errorMessage != "" {
+	if err := json.NewEncoder(w).Encode(responseBody); err != nil {
 		return &httperror.HTTPError{
-			Cause: nil,
+			Cause: err,
+			Type:  httperror.TypeInternal,
 			Info: httperror.ErrorMessage{
-				Title:  "Invalid post info",
-				Detail: errorMessage,
+				Title:  "Internal server error",
+				Detail: "",
 			},
-			Code: 400,
+			Code: 500,
 		}
 	}
 
-	if err := database.AddPost(&post); err != nil {
+	return nil
+}
+
+// GetPost handles the GET requests for /posts/{id} route.
+// Responses with the post and its ETag.
+func (h *Handler) GetPost(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	id, httpErr := postID(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	post, httpErr := h.findPost(r, id, false)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	posts := []core.Post{*post}
+	if httpErr := h.loadReactions(r, posts); httpErr != nil {
+		return httpErr
+	}
+	post = &posts[0]
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", postETag(post))
+	if err := json.NewEncoder(w).Encode(post); err != nil {
 		return &httperror.HTTPError{
 			Cause: err,
+			Type:  httperror.TypeInternal,
 			Info: httperror.ErrorMessage{
-				Title:  "Interal server error",
-				Detail: err.Error(),
+				Title:  "Internal server error",
+				Detail: "",
 			},
 			Code: 500,
 		}
 	}
-	w.WriteHeader(201)
 
 	return nil
 }
 
-func GetPostsAmount(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
-	count, err := database.CountPosts()
-	if err != nil {
+func (h *Handler) AddPost(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	var post core.Post
+	if httpErr := request.DecodeRequestBody(r, &post); httpErr != nil {
+		return httpErr
+	}
+
+	post.User = Username(r)
+
+	if post.Status == "" {
+		post.Status = core.PostPublished
+	}
+	if errs := validation.Post(&post); errs != nil {
+		return httperror.Invalid(httperror.TypeInvalidPost, "Invalid post info", errs)
+	}
+	// Tags are valid, so they are only normalized here
+	post.Tags, _ = core.NormalizeTags(post.Tags)
+
+	if err := h.store.AddPost(&post); err != nil {
+		if err == database.ErrInvalidAttachment {
+			return httperror.Invalid(httperror.TypeInvalidPost, "Invalid post info", validation.Errors{
+				{Field: "attachment_ids", Code: validation.CodeInvalid, Message: "Attachments must be uploaded by the sender and not attached to another post"},
+			})
+		}
 		return &httperror.HTTPError{
 			Cause: err,
+			Type:  httperror.TypeInternal,
 			Info: httperror.ErrorMessage{
 				Title:  "Internal server error",
-				Detail: err.Error(),
+				Detail: "",
 			},
 			Code: 500,
 		}
 	}
+	if post.Status == core.PostPublished {
+		h.hub.Publish(stream.Event{ID: database.CursorOf(post).String(), Type: PostAddedEvent, Data: post})
+	}
+	w.WriteHeader(201)
+
+	return nil
+}
+
+// GetPostsAmount returns the number of posts.
+// Like the listings it is answered with Not Modified when the posts have not changed,
+// so polling it is cheap.
+func (h *Handler) GetPostsAmount(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	query, httpErr := queryPosts(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	state, httpErr := h.listingState(query)
+	if httpErr != nil {
+		return httpErr
+	}
+	if notModified(w, r, state, false) {
+		return nil
+	}
 
 	responseBody := response.PostsResponse{
 		Posts: nil,
-		Count: count,
+		Count: state.Count,
 	}
 
 	if err := json.NewEncoder(w).Encode(responseBody); err != nil {
 		return &httperror.HTTPError{
 			Cause: err,
+			Type:  httperror.TypeInternal,
 			Info: httperror.ErrorMessage{
 				Title:  "Internal server error",
-				Detail: err.Error(),
+				Detail: "",
 			},
 			Code: 500,
 		}
@@ -0,0 +1,104 @@
+package httphandlers
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/furkanpala/post-app/internal/core"
+	"github.com/furkanpala/post-app/internal/http/response"
+)
+
+func TestGetPostsOnPage(t *testing.T) {
+	s := newTestServer(t)
+	ids := s.addPosts("alice", 2*PostsPerPage+2)
+
+	w := s.do("GET", "/posts?page=1", "", "")
+	expectStatus(t, w, 200)
+	var first response.PostsResponse
+	decode(t, w, &first)
+	if len(first.Posts) != PostsPerPage || first.Posts[0].ID != ids[len(ids)-1] {
+		t.Fatalf("first page has %d posts starting at %d, want %d starting at %d",
+			len(first.Posts), first.Posts[0].ID, PostsPerPage, ids[len(ids)-1])
+	}
+
+	w = s.do("GET", "/posts?page=3", "", "")
+	expectStatus(t, w, 200)
+	var last response.PostsResponse
+	decode(t, w, &last)
+	if len(last.Posts) != 2 || last.Posts[1].ID != ids[0] {
+		t.Fatalf("last page has %d posts, want the 2 oldest", len(last.Posts))
+	}
+
+	expectStatus(t, s.do("GET", "/posts?page=4", "", ""), 404)
+	expectStatus(t, s.do("GET", "/posts?page=0", "", ""), 404)
+	expectStatus(t, s.do("GET", "/posts?page=first", "", ""), 400)
+}
+
+func TestGetPostsAfterCursor(t *testing.T) {
+	s := newTestServer(t)
+	ids := s.addPosts("alice", 2*PostsPerPage+2)
+
+	seen := make(map[int]bool)
+	cursor := ""
+	for page := 1; ; page++ {
+		w := s.do("GET", "/posts?cursor="+url.QueryEscape(cursor), "", "")
+		expectStatus(t, w, 200)
+		var body response.PostsResponse
+		decode(t, w, &body)
+
+		for _, post := range body.Posts {
+			if seen[post.ID] {
+				t.Fatalf("post %d is listed twice", post.ID)
+			}
+			seen[post.ID] = true
+		}
+
+		// Posts added while paging must not shift the next pages
+		if page == 1 {
+			s.addPosts("bob", 3)
+		}
+
+		if body.NextCursor == "" {
+			break
+		}
+		cursor = body.NextCursor
+	}
+
+	if len(seen) != len(ids) {
+		t.Fatalf("listed %d posts, want %d", len(seen), len(ids))
+	}
+	for _, id := range ids {
+		if !seen[id] {
+			t.Fatalf("post %d is not listed", id)
+		}
+	}
+
+	expectStatus(t, s.do("GET", "/posts?cursor=not-a-cursor", "", ""), 400)
+}
+
+func TestGetLegacyPage(t *testing.T) {
+	s := newTestServer(t)
+	ids := s.addPosts("alice", PostsPerPage+2)
+
+	w := s.do("GET", "/posts/2?as=page", "", "")
+	expectStatus(t, w, 200)
+	var page response.PostsResponse
+	decode(t, w, &page)
+	if len(page.Posts) != 2 || page.Posts[1].ID != ids[0] {
+		t.Fatalf("second page has %d posts, want the 2 oldest", len(page.Posts))
+	}
+	if w.Header().Get("Deprecation") != "true" || w.Header().Get("Link") != `</posts?page=2>; rel="successor-version"` {
+		t.Fatalf("legacy page is not deprecated, headers: %v", w.Header())
+	}
+
+	// Without the flag the path addresses a post, whatever the client accepts
+	for _, accept := range []string{"application/json", "application/vnd.post-app.legacy+json"} {
+		w = s.do("GET", "/posts/2", "", "", "Accept", accept)
+		expectStatus(t, w, 200)
+		var post core.Post
+		decode(t, w, &post)
+		if post.ID != 2 {
+			t.Fatalf("GET /posts/2 accepting %s returned post %d", accept, post.ID)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/furkanpala/post-app/internal/core"
+	httperror "github.com/furkanpala/post-app/internal/http/error"
+	"github.com/furkanpala/post-app/internal/http/response"
+	"github.com/gorilla/mux"
+)
+
+// PutReaction handles the PUT requests for /posts/{id}/reactions/{type} route.
+// A user can give each type of reaction once, giving it again changes nothing.
+// Responses with the reaction counts of the post.
+func (h *Handler) PutReaction(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	return h.changeReaction(w, r, h.store.AddReaction)
+}
+
+// DeleteReaction handles the DELETE requests for /posts/{id}/reactions/{type} route.
+// Responses with the reaction counts of the post.
+func (h *Handler) DeleteReaction(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	return h.changeReaction(w, r, h.store.RemoveReaction)
+}
+
+// changeReaction applies change to the reaction of the request
+// and responses with the reaction counts of the post
+func (h *Handler) changeReaction(w http.ResponseWriter, r *http.Request,
+	change func(postID int, username, reaction string) error) *httperror.HTTPError {
+	id, httpErr := postID(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	reaction := mux.Vars(r)["type"]
+	if !h.reactionTypes[reaction] {
+		return &httperror.HTTPError{
+			Cause: nil,
+			Type:  httperror.TypeInvalidReaction,
+			Info: httperror.ErrorMessage{
+				Title:  "Invalid reaction",
+				Detail: "Reaction must be one of " + strings.Join(h.sortedReactionTypes(), ", "),
+			},
+			Code: 400,
+		}
+	}
+
+	post, httpErr := h.findPost(r, id, false)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	username := Username(r)
+	if err := change(id, username, reaction); err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	posts := []core.Post{*post}
+	if httpErr := h.loadReactions(r, posts); httpErr != nil {
+		return httpErr
+	}
+
+	responseBody := response.ReactionsResponse{
+		Reactions: posts[0].Reactions,
+		Reacted:   posts[0].Reacted,
+	}
+	if responseBody.Reactions == nil {
+		responseBody.Reactions = map[string]int{}
+	}
+	if responseBody.Reacted == nil {
+		responseBody.Reacted = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(responseBody); err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	return nil
+}
+
+// loadReactions fills in the reaction counts of the posts
+// and the reactions of the authenticated user of the request, if any
+func (h *Handler) loadReactions(r *http.Request, posts []core.Post) *httperror.HTTPError {
+	username := Username(r)
+
+	if err := h.store.LoadReactions(posts, username); err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	return nil
+}
+
+// sortedReactionTypes returns the allowed reaction types in alphabetical order
+func (h *Handler) sortedReactionTypes() []string {
+	types := make([]string, 0, len(h.reactionTypes))
+	for t := range h.reactionTypes {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	return types
+}
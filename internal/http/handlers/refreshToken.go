@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
-	"github.com/furkanpala/post-app/internal/database"
 	"github.com/furkanpala/post-app/internal/env"
 	httperror "github.com/furkanpala/post-app/internal/http/error"
 	"github.com/furkanpala/post-app/internal/http/response"
@@ -16,7 +15,7 @@ import (
 // RefreshToken handles the requests for /token route.
 // Verifies the incoming refresh token.
 // If it is valid, then responses with a new refresh and an access token
-func RefreshToken(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+func (h *Handler) RefreshToken(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
 	cookie, httpErr := jwttoken.ParseCookie(r, "jid")
 	if httpErr != nil {
 		return httpErr
@@ -33,6 +32,7 @@ func RefreshToken(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
 		case *jwt.ValidationError:
 			return &httperror.HTTPError{
 				Cause: err,
+				Type:  httperror.TypeInvalidToken,
 				Info: httperror.ErrorMessage{
 					Title:  "Unauthorized",
 					Detail: "Invalid credentials",
@@ -42,6 +42,7 @@ func RefreshToken(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
 		default:
 			return &httperror.HTTPError{
 				Cause: err,
+				Type:  httperror.TypeInternal,
 				Info: httperror.ErrorMessage{
 					Title:  "Internal server error",
 					Detail: "",
@@ -54,6 +55,7 @@ func RefreshToken(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
 	if !token.Valid {
 		return &httperror.HTTPError{
 			Cause: nil,
+			Type:  httperror.TypeInvalidToken,
 			Info: httperror.ErrorMessage{
 				Title:  "Unauthorized",
 				Detail: "Invalid credentials",
@@ -63,10 +65,11 @@ func RefreshToken(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
 	}
 
 	// Check if the given token's jti is in blacklist
-	isInBlacklist, err := database.FindJTI(claims.Id)
+	isInBlacklist, err := h.store.FindJTI(claims.Id)
 	if err != nil {
 		return &httperror.HTTPError{
 			Cause: err,
+			Type:  httperror.TypeInternal,
 			Info: httperror.ErrorMessage{
 				Title:  "Internal server error",
 				Detail: "",
@@ -81,6 +84,7 @@ func RefreshToken(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
 	if isInBlacklist {
 		return &httperror.HTTPError{
 			Cause: nil,
+			Type:  httperror.TypeRevokedToken,
 			Info: httperror.ErrorMessage{
 				Title:  "Unauthorized",
 				Detail: "Invalid credentials",
@@ -94,6 +98,7 @@ func RefreshToken(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
 	if err != nil {
 		return &httperror.HTTPError{
 			Cause: err,
+			Type:  httperror.TypeInternal,
 			Info: httperror.ErrorMessage{
 				Title:  "Internal server error",
 				Detail: "",
@@ -107,6 +112,7 @@ func RefreshToken(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
 	if err != nil {
 		return &httperror.HTTPError{
 			Cause: err,
+			Type:  httperror.TypeInternal,
 			Info: httperror.ErrorMessage{
 				Title:  "Internal server error",
 				Detail: "",
@@ -4,14 +4,14 @@ import (
 	"net/http"
 
 	"github.com/furkanpala/post-app/internal/core"
-	"github.com/furkanpala/post-app/internal/database"
 	httperror "github.com/furkanpala/post-app/internal/http/error"
 	"github.com/furkanpala/post-app/internal/http/request"
+	"github.com/furkanpala/post-app/internal/validation"
 )
 
 // HandleRegister function handles the request for /register route.
 // Adds the user into database if request is correct
-func HandleRegister(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+func (h *Handler) HandleRegister(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
 	var user core.User
 
 	// Parse request body
@@ -20,41 +20,17 @@ func HandleRegister(w http.ResponseWriter, r *http.Request) *httperror.HTTPError
 	}
 
 	// Check username and password length
-	usernameLength, passwordLength := len(user.Username), len(user.Password)
-	errorMessage := ""
-	if usernameLength < 3 {
-		errorMessage += "Too short username - Minimum 3 characters"
-	}
-	if passwordLength < 6 {
-		if errorMessage != "" {
-			errorMessage += "|"
-		}
-		errorMessage += "Too short password - Minimum 6 characters"
-	}
-	if usernameLength > 20 {
-		if errorMessage != "" {
-			errorMessage += "|"
-		}
-		errorMessage += "Too long password - Maximum 20 characters"
-	}
-
-	if errorMessage != "" {
-		return &httperror.HTTPError{
-			Cause: nil,
-			Info: httperror.ErrorMessage{
-				Title:  "Invalid register credentials",
-				Detail: errorMessage,
-			},
-			Code: 400,
-		}
+	if errs := validation.User(&user); errs != nil {
+		return httperror.Invalid(httperror.TypeInvalidRegistration, "Invalid register credentials", errs)
 	}
 
 	// Check if users already exists
-	userExists, err := database.FindUser(user.Username)
+	userExists, err := h.store.FindUser(user.Username)
 
 	if err != nil {
 		return &httperror.HTTPError{
 			Cause: err,
+			Type:  httperror.TypeInternal,
 			Info: httperror.ErrorMessage{
 				Title:  "Internal server error",
 				Detail: "",
@@ -66,6 +42,7 @@ func HandleRegister(w http.ResponseWriter, r *http.Request) *httperror.HTTPError
 	if userExists != nil {
 		return &httperror.HTTPError{
 			Cause: nil,
+			Type:  httperror.TypeUserExists,
 			Info: httperror.ErrorMessage{
 				Title:  "User already exists",
 				Detail: "",
@@ -78,6 +55,7 @@ func HandleRegister(w http.ResponseWriter, r *http.Request) *httperror.HTTPError
 	if err := user.HashPassword(); err != nil {
 		return &httperror.HTTPError{
 			Cause: err,
+			Type:  httperror.TypeInternal,
 			Info: httperror.ErrorMessage{
 				Title:  "Internal server error",
 				Detail: "",
@@ -87,9 +65,10 @@ func HandleRegister(w http.ResponseWriter, r *http.Request) *httperror.HTTPError
 	}
 
 	// Add into database
-	if err := database.AddUser(&user); err != nil {
+	if err := h.store.AddUser(&user); err != nil {
 		return &httperror.HTTPError{
 			Cause: err,
+			Type:  httperror.TypeInternal,
 			Info: httperror.ErrorMessage{
 				Title:  "Internal server error",
 				Detail: "",
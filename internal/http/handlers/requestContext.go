@@ -0,0 +1,53 @@
+package httphandlers
+
+import (
+	"context"
+	"net/http"
+
+	httperror "github.com/furkanpala/post-app/internal/http/error"
+)
+
+// contextKey is the type of the keys of the values put into request contexts
+type contextKey string
+
+// usernameKey is the key of the username of the authenticated user
+const usernameKey contextKey = "username"
+
+// WithUsername returns a shallow copy of r whose context carries the username of the authenticated user
+func WithUsername(r *http.Request, username string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), usernameKey, username))
+}
+
+// Username returns the username of the authenticated user of the request, empty if there is none
+func Username(r *http.Request) string {
+	username, _ := r.Context().Value(usernameKey).(string)
+	return username
+}
+
+// canChange reports whether the authenticated user of the request can change
+// a post or a comment sent by owner, which are the owner and the admins.
+// The user is only looked up when they are not the owner.
+func (h *Handler) canChange(r *http.Request, owner string) (bool, *httperror.HTTPError) {
+	username := Username(r)
+	if username == "" {
+		return false, nil
+	}
+	if username == owner {
+		return true, nil
+	}
+
+	user, err := h.store.FindUser(username)
+	if err != nil {
+		return false, &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	return user != nil && user.Admin, nil
+}
@@ -0,0 +1,256 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/furkanpala/post-app/internal/core"
+	"github.com/furkanpala/post-app/internal/database"
+	"github.com/furkanpala/post-app/internal/diff"
+	httperror "github.com/furkanpala/post-app/internal/http/error"
+	"github.com/furkanpala/post-app/internal/http/response"
+	"github.com/gorilla/mux"
+)
+
+// GetRevisions handles the requests for /posts/{id}/revisions route.
+// Returns every version of the post, oldest first.
+// The last revision is the current version of the post.
+func (h *Handler) GetRevisions(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	id, httpErr := postID(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	post, httpErr := h.findPost(r, id, false)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	revisions, err := h.store.ListRevisions(id)
+	if err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+	revisions = append(revisions, currentRevision(post))
+
+	responseBody := response.RevisionsResponse{
+		Revisions: revisions,
+		Count:     len(revisions),
+	}
+
+	if err := json.NewEncoder(w).Encode(responseBody); err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	return nil
+}
+
+// GetRevision handles the requests for /posts/{id}/revisions/{n} route.
+// Returns the nth version of the post.
+func (h *Handler) GetRevision(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	id, httpErr := postID(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	n, httpErr := revisionNumber(mux.Vars(r)["n"])
+	if httpErr != nil {
+		return httpErr
+	}
+
+	post, httpErr := h.findPost(r, id, false)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	revision, httpErr := h.findRevision(post, n)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if err := json.NewEncoder(w).Encode(revision); err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	return nil
+}
+
+// GetRevisionDiff handles the requests for /posts/{id}/revisions/{n}/diff route.
+// Returns the line-based diff from the revision given by from query parameter,
+// the previous revision by default, to the nth revision.
+// The first revision is diffed against an empty post.
+func (h *Handler) GetRevisionDiff(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	id, httpErr := postID(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	to, httpErr := revisionNumber(mux.Vars(r)["n"])
+	if httpErr != nil {
+		return httpErr
+	}
+
+	from := to - 1
+	if fromParam := r.URL.Query().Get("from"); fromParam != "" {
+		if from, httpErr = revisionNumber(fromParam); httpErr != nil {
+			return httpErr
+		}
+	}
+
+	if from >= to {
+		return &httperror.HTTPError{
+			Cause: nil,
+			Type:  httperror.TypeInvalidRevision,
+			Info: httperror.ErrorMessage{
+				Title:  "Invalid revision",
+				Detail: "Revision to diff from must be before revision " + strconv.Itoa(to),
+			},
+			Code: 400,
+		}
+	}
+
+	post, httpErr := h.findPost(r, id, false)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	toRevision, httpErr := h.findRevision(post, to)
+	if httpErr != nil {
+		return httpErr
+	}
+	// Revision 0 is the empty post before the first revision
+	fromRevision := &core.Revision{PostID: id}
+	if from > 0 {
+		if fromRevision, httpErr = h.findRevision(post, from); httpErr != nil {
+			return httpErr
+		}
+	}
+
+	titleDiff, titleErr := diff.Lines(fromRevision.Title, toRevision.Title)
+	contentDiff, contentErr := diff.Lines(fromRevision.Content, toRevision.Content)
+	if titleErr != nil || contentErr != nil {
+		return &httperror.HTTPError{
+			Cause: nil,
+			Type:  httperror.TypeDiffTooLarge,
+			Info: httperror.ErrorMessage{
+				Title:  "Diff too large",
+				Detail: "Revisions differ in too many lines to compare",
+			},
+			Code: 422,
+		}
+	}
+
+	responseBody := response.RevisionDiffResponse{
+		PostID:  id,
+		From:    from,
+		To:      to,
+		Title:   titleDiff,
+		Content: contentDiff,
+	}
+
+	if err := json.NewEncoder(w).Encode(responseBody); err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	return nil
+}
+
+// findRevision returns the nth version of post, which is post itself if n is its version
+func (h *Handler) findRevision(post *core.Post, n int) (*core.Revision, *httperror.HTTPError) {
+	if n == post.Version {
+		revision := currentRevision(post)
+		return &revision, nil
+	}
+
+	revision, err := h.store.FindRevision(post.ID, n)
+	if err == database.ErrNotFound {
+		return nil, &httperror.HTTPError{
+			Cause: nil,
+			Type:  httperror.TypeRevisionNotFound,
+			Info: httperror.ErrorMessage{
+				Title:  "Revision not found",
+				Detail: "",
+			},
+			Code: 404,
+		}
+	}
+	if err != nil {
+		return nil, &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	return revision, nil
+}
+
+// currentRevision returns the current version of post as a revision
+func currentRevision(post *core.Post) core.Revision {
+	revision := core.Revision{
+		PostID:  post.ID,
+		Number:  post.Version,
+		Title:   post.Title,
+		Content: post.Content,
+		Date:    post.Date,
+	}
+	if post.EditedAt != 0 {
+		revision.Date = post.EditedAt
+	}
+
+	return revision
+}
+
+// revisionNumber parses a revision number
+func revisionNumber(s string) (int, *httperror.HTTPError) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, &httperror.HTTPError{
+			Cause: nil,
+			Type:  httperror.TypeInvalidRevision,
+			Info: httperror.ErrorMessage{
+				Title:  "Invalid revision",
+				Detail: "Revision must be an integer greater than zero",
+			},
+			Code: 400,
+		}
+	}
+
+	return n, nil
+}
@@ -2,32 +2,54 @@ package httphandlers
 
 import (
 	"encoding/json"
-	"fmt"
+	"log"
 	"net/http"
 
 	httperror "github.com/furkanpala/post-app/internal/http/error"
 )
 
+// RequestIDHeader carries the id of the request given by middleware.RequestID,
+// both on the request and on its response
+const RequestIDHeader = "X-Request-ID"
+
 // RouteHandler is a custom handler function that returns a custom HTTP Error
 type RouteHandler func(http.ResponseWriter, *http.Request) *httperror.HTTPError
 
+// ServeHTTP responses with the error returned by fn, if any, as problem details
+// unless the client accepts the legacy format.
+// The error is logged with the id of the request, server errors are only sent
+// with the id, see httperror.HTTPError.Public.
 func (fn RouteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	err := fn(w, r)
 	if err == nil {
 		return
 	}
 
-	body, parseError := json.Marshal(err)
+	requestID := r.Header.Get(RequestIDHeader)
+	if err.Cause != nil {
+		log.Printf("Request %s: %s: %v\n", requestID, err.Info.Title, err.Cause)
+	} else if err.Code >= 500 {
+		log.Printf("Request %s: %s: %s\n", requestID, err.Info.Title, err.Info.Detail)
+	}
+
+	public := err.Public(requestID)
+
+	contentType := httperror.ProblemMediaType
+	var body []byte
+	var parseError error
+	if httperror.WantsLegacy(r) {
+		contentType = "application/json"
+		body, parseError = json.Marshal(public)
+	} else {
+		body, parseError = json.Marshal(public.Problem(requestID))
+	}
 
 	if parseError != nil {
+		log.Printf("Request %s: parse error: %v\n", requestID, parseError)
 		w.WriteHeader(500)
-		fmt.Printf("Parse error: %v", parseError)
 		return
 	}
-	if err.Cause != nil {
-		fmt.Printf("%v\n", err.Cause)
-	}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(err.Code)
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(public.Code)
 	w.Write(body)
 }
@@ -0,0 +1,86 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/furkanpala/post-app/internal/database"
+	httperror "github.com/furkanpala/post-app/internal/http/error"
+	"github.com/furkanpala/post-app/internal/http/response"
+)
+
+// SearchPosts handles the requests for /posts/search route.
+// q query parameter is the search text, author parameter filters by the sender
+// and page parameter selects the page of results, 1 by default.
+// Results are ranked, best match first.
+func (h *Handler) SearchPosts(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	params := r.URL.Query()
+
+	text := strings.TrimSpace(params.Get("q"))
+	if text == "" {
+		return &httperror.HTTPError{
+			Cause: nil,
+			Type:  httperror.TypeInvalidSearch,
+			Info: httperror.ErrorMessage{
+				Title:  "Invalid search",
+				Detail: "Search query required",
+			},
+			Code: 400,
+		}
+	}
+
+	page := 1
+	if pageParam := params.Get("page"); pageParam != "" {
+		var err error
+		if page, err = strconv.Atoi(pageParam); err != nil || page <= 0 {
+			return &httperror.HTTPError{
+				Cause: nil,
+				Type:  httperror.TypeInvalidPage,
+				Info: httperror.ErrorMessage{
+					Title:  "Invalid page",
+					Detail: "Page must be an integer greater than zero",
+				},
+				Code: 400,
+			}
+		}
+	}
+
+	results, err := h.store.SearchPosts(database.SearchQuery{
+		Text:   text,
+		Author: params.Get("author"),
+		Limit:  PostsPerPage,
+		Offset: PostsPerPage * (page - 1),
+	})
+	if err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	responseBody := response.SearchResponse{
+		Posts: results,
+		Count: len(results),
+	}
+
+	if err := json.NewEncoder(w).Encode(responseBody); err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	return nil
+}
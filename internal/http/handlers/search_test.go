@@ -0,0 +1,45 @@
+package httphandlers
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/furkanpala/post-app/internal/http/response"
+)
+
+func TestSearchPosts(t *testing.T) {
+	s := newTestServer(t)
+	s.addPosts("alice", PostsPerPage+1)
+	bobs := s.addPosts("bob", 1)
+
+	w := s.do("GET", "/posts/search?q="+url.QueryEscape(`"post 1"`), "", "")
+	expectStatus(t, w, 200)
+	var phrase response.SearchResponse
+	decode(t, w, &phrase)
+	// "Post 1" of alice and of bob
+	if phrase.Count != 2 || len(phrase.Posts) != 2 {
+		t.Fatalf("phrase search found %d posts, want 2", phrase.Count)
+	}
+
+	w = s.do("GET", "/posts/search?q=post&author=bob", "", "")
+	expectStatus(t, w, 200)
+	var byBob response.SearchResponse
+	decode(t, w, &byBob)
+	if byBob.Count != 1 || byBob.Posts[0].ID != bobs[0] {
+		t.Fatalf("search of bob's posts found %d posts, want post %d", byBob.Count, bobs[0])
+	}
+	if byBob.Posts[0].TitleHighlight != "<mark>Post</mark> 1" {
+		t.Fatalf("title highlight is %q", byBob.Posts[0].TitleHighlight)
+	}
+
+	w = s.do("GET", "/posts/search?q=post&page=2", "", "")
+	expectStatus(t, w, 200)
+	var second response.SearchResponse
+	decode(t, w, &second)
+	if second.Count != 2 {
+		t.Fatalf("second page of results has %d posts, want 2", second.Count)
+	}
+
+	expectStatus(t, s.do("GET", "/posts/search?q=+", "", ""), 400)
+	expectStatus(t, s.do("GET", "/posts/search?q=post&page=0", "", ""), 400)
+}
@@ -0,0 +1,194 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/furkanpala/post-app/internal/core"
+	"github.com/furkanpala/post-app/internal/database"
+	httperror "github.com/furkanpala/post-app/internal/http/error"
+	"github.com/furkanpala/post-app/internal/stream"
+)
+
+// Types of the events sent by /posts/stream
+const (
+	PostAddedEvent     = "post_added"
+	PostPublishedEvent = "post_published"
+	PostEditedEvent    = "post_edited"
+	PostDeletedEvent   = "post_deleted"
+	PostRestoredEvent  = "post_restored"
+	// StreamResetEvent tells a resuming client that it missed too many posts to replay,
+	// it should reload the listings
+	StreamResetEvent = "reset"
+)
+
+const (
+	// StreamHeartbeat is the interval of the comments sent to keep idle streams open
+	StreamHeartbeat = 15 * time.Second
+	// StreamReplayLimit is the maximum number of posts replayed when a stream is resumed,
+	// a client which missed more receives a reset event instead
+	StreamReplayLimit = 100
+)
+
+// deletedPost is the data of post_deleted events
+type deletedPost struct {
+	ID int `json:"id"`
+}
+
+// StreamPosts handles the GET requests for /posts/stream route.
+// Sends the changes of published posts as Server-Sent Events until the client disconnects.
+// Only post_added and post_published events have ids, which point at the posts in the order they were published.
+// A client reconnecting with Last-Event-ID first receives the posts published since, oldest first,
+// or a reset event if there are more than StreamReplayLimit of them.
+func (h *Handler) StreamPosts(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return &httperror.HTTPError{
+			Cause: errors.New("response writer does not support flushing"),
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	var last *database.Cursor
+	if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" {
+		cursor, err := database.ParseCursor(lastEventID)
+		if err != nil {
+			return &httperror.HTTPError{
+				Cause: nil,
+				Type:  httperror.TypeInvalidLastEventID,
+				Info: httperror.ErrorMessage{
+					Title:  "Invalid Last-Event-ID",
+					Detail: "Last-Event-ID must be the id of a post_added or post_published event",
+				},
+				Code: 400,
+			}
+		}
+		last = &cursor
+	}
+
+	// Subscribing before the replay so that nothing published in between is missed
+	events, unsubscribe := h.hub.Subscribe()
+	defer unsubscribe()
+
+	var replay []stream.Event
+	if last != nil {
+		var httpErr *httperror.HTTPError
+		if replay, httpErr = h.replayEvents(*last); httpErr != nil {
+			return httpErr
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(200)
+
+	// The response has started, errors can only end the stream from now on
+	replayed := make(map[string]bool, len(replay))
+	for _, event := range replay {
+		if err := writeEvent(w, event); err != nil {
+			return nil
+		}
+		replayed[event.ID] = true
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(StreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return nil
+		case event, ok := <-events:
+			// Closed when the client falls behind, it can resume with Last-Event-ID
+			if !ok {
+				return nil
+			}
+			if event.ID != "" && replayed[event.ID] {
+				continue
+			}
+			if err := writeEvent(w, event); err != nil {
+				return nil
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// replayEvents returns the events of the posts published after the cursor, oldest first,
+// or a reset event pointing at the newest post if there are more than StreamReplayLimit of them
+func (h *Handler) replayEvents(last database.Cursor) ([]stream.Event, *httperror.HTTPError) {
+	posts, err := h.store.ListPosts(database.PostQuery{PublishedAfter: &last, Sort: database.SortOldest, Limit: StreamReplayLimit + 1})
+	if err != nil {
+		return nil, &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	if len(posts) > StreamReplayLimit {
+		newest, err := h.store.ListPosts(database.PostQuery{Limit: 1})
+		if err != nil {
+			return nil, &httperror.HTTPError{
+				Cause: err,
+				Type:  httperror.TypeInternal,
+				Info: httperror.ErrorMessage{
+					Title:  "Internal server error",
+					Detail: "",
+				},
+				Code: 500,
+			}
+		}
+		return []stream.Event{{ID: database.CursorOf(newest[0]).String(), Type: StreamResetEvent, Data: struct{}{}}}, nil
+	}
+
+	events := make([]stream.Event, 0, len(posts))
+	for _, post := range posts {
+		events = append(events, stream.Event{ID: database.CursorOf(post).String(), Type: PostAddedEvent, Data: post})
+	}
+
+	return events, nil
+}
+
+// PublishScheduled streams the posts published by the scheduler, it is meant to be passed to
+// database.RunPublishScheduler
+func (h *Handler) PublishScheduled(posts []core.Post) {
+	for _, post := range posts {
+		h.hub.Publish(stream.Event{ID: database.CursorOf(post).String(), Type: PostPublishedEvent, Data: post})
+	}
+}
+
+// writeEvent writes event in the Server-Sent Events format
+func writeEvent(w http.ResponseWriter, event stream.Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+
+	if event.ID != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", event.ID); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+
+	return err
+}
@@ -0,0 +1,155 @@
+package httphandlers
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/furkanpala/post-app/internal/core"
+	"github.com/furkanpala/post-app/internal/database"
+)
+
+// sentEvent is an event as it is read from the stream
+type sentEvent struct {
+	id, event, data string
+}
+
+// parseEvents parses the Server-Sent Events in body, skipping the comments
+func parseEvents(body string) []sentEvent {
+	var events []sentEvent
+	for _, block := range strings.Split(body, "\n\n") {
+		var event sentEvent
+		for _, line := range strings.Split(block, "\n") {
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				event.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				event.event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				event.data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+		if event.event != "" {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+// resume requests the stream with Last-Event-ID and returns the response to it
+// without waiting for live events, as the client disconnects right after the replay
+func (s *testServer) resume(lastEventID string) *httptest.ResponseRecorder {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	r := httptest.NewRequest("GET", "/posts/stream", nil).WithContext(ctx)
+	r.Header.Set("Last-Event-ID", lastEventID)
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, r)
+
+	return w
+}
+
+// cursorOf returns the event id of the post with given id
+func (s *testServer) cursorOf(id int) string {
+	post, err := s.store.FindPost(id)
+	if err != nil {
+		s.t.Fatalf("FindPost: %v", err)
+	}
+
+	return database.CursorOf(*post).String()
+}
+
+func TestStreamReplay(t *testing.T) {
+	s := newTestServer(t)
+	ids := s.addPosts("alice", 3)
+
+	w := s.resume(s.cursorOf(ids[0]))
+	expectStatus(t, w, 200)
+	events := parseEvents(w.Body.String())
+	if len(events) != 2 {
+		t.Fatalf("replayed %d events, want the 2 posts published since: %s", len(events), w.Body.String())
+	}
+	for i, id := range ids[1:] {
+		if events[i].event != PostAddedEvent || events[i].id != s.cursorOf(id) {
+			t.Fatalf("event %d is %+v, want %s of post %d", i, events[i], PostAddedEvent, id)
+		}
+	}
+
+	// Nothing is replayed to a client which is up to date
+	w = s.resume(s.cursorOf(ids[2]))
+	expectStatus(t, w, 200)
+	if events := parseEvents(w.Body.String()); len(events) != 0 {
+		t.Fatalf("replayed %v to an up to date client, want nothing", events)
+	}
+}
+
+func TestStreamReplayReset(t *testing.T) {
+	s := newTestServer(t)
+	ids := s.addPosts("alice", StreamReplayLimit+2)
+
+	w := s.resume(s.cursorOf(ids[0]))
+	expectStatus(t, w, 200)
+	events := parseEvents(w.Body.String())
+	if len(events) != 1 || events[0].event != StreamResetEvent || events[0].id != s.cursorOf(ids[len(ids)-1]) {
+		t.Fatalf("replayed %v to a client which missed %d posts, want a reset to the newest post", events, len(ids)-1)
+	}
+}
+
+func TestStreamInvalidLastEventID(t *testing.T) {
+	s := newTestServer(t)
+
+	for _, id := range []string{"nonsense", "1", "-5_2"} {
+		w := s.resume(id)
+		expectStatus(t, w, 400)
+	}
+}
+
+func TestStreamPublishScheduled(t *testing.T) {
+	s := newTestServer(t)
+	server := httptest.NewServer(s.router)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r, err := http.NewRequest("GET", server.URL+"/posts/stream", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(r.WithContext(ctx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	// The client is subscribed once the response has started
+	post := core.Post{Title: "Scheduled", Content: "Content", User: "alice", Status: core.PostScheduled, PublishAt: 1}
+	if err := s.store.AddPost(&post); err != nil {
+		t.Fatal(err)
+	}
+	published, err := s.store.PublishScheduledPosts(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.handler.PublishScheduled(published)
+
+	var block strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() && scanner.Text() != "" {
+		block.WriteString(scanner.Text() + "\n")
+	}
+	events := parseEvents(block.String())
+	if len(events) != 1 || events[0].event != PostPublishedEvent || events[0].id != s.cursorOf(post.ID) {
+		t.Fatalf("stream sent %v, want %s of post %d", events, PostPublishedEvent, post.ID)
+	}
+	if !strings.Contains(events[0].data, `"title":"Scheduled"`) {
+		t.Fatalf("event data is %s, want the published post", events[0].data)
+	}
+}
@@ -0,0 +1,45 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	httperror "github.com/furkanpala/post-app/internal/http/error"
+	"github.com/furkanpala/post-app/internal/http/response"
+)
+
+// GetTags handles the GET requests for /tags route.
+// Returns the tags of the posts with the number of posts tagged with them, most used first.
+func (h *Handler) GetTags(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	tags, err := h.store.ListTags()
+	if err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	responseBody := response.TagsResponse{
+		Tags:  tags,
+		Count: len(tags),
+	}
+
+	if err := json.NewEncoder(w).Encode(responseBody); err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package httphandlers
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/furkanpala/post-app/internal/core"
+	"github.com/furkanpala/post-app/internal/http/response"
+)
+
+func TestTagFilters(t *testing.T) {
+	s := newTestServer(t)
+	s.addPosts("alice", 2)
+
+	var tags []string
+	for i := 0; i < core.MaxTags; i++ {
+		tags = append(tags, `"tag`+strconv.Itoa(i)+`"`)
+	}
+	w := s.do("POST", "/posts", "alice", `{"title":"Tagged","content":"Content","tags":[`+strings.Join(tags, ",")+`]}`)
+	expectStatus(t, w, 201)
+
+	w = s.do("GET", "/posts?tag=TAG0&tag=%23tag9", "", "")
+	expectStatus(t, w, 200)
+	var listing response.PostsResponse
+	decode(t, w, &listing)
+	if len(listing.Posts) != 1 || listing.Posts[0].Title != "Tagged" {
+		t.Fatalf("listing filtered by tags has %d posts, want the tagged post", len(listing.Posts))
+	}
+
+	filters := "/posts?tag=" + strings.Repeat("a", core.MaxTagLength+1)
+	expectStatus(t, s.do("GET", filters, "", ""), 400)
+
+	filters = "/posts?tag=extra"
+	for i := 0; i < core.MaxTagFilters; i++ {
+		filters += "&tag=tag" + strconv.Itoa(i)
+	}
+	w = s.do("GET", filters, "", "")
+	expectStatus(t, w, 400)
+	if !strings.Contains(w.Body.String(), core.ErrTooManyTagFilters.Error()) {
+		t.Fatalf("too many tag filters are not reported as such: %s", w.Body.String())
+	}
+
+	tags = append(tags, `"extra"`)
+	w = s.do("POST", "/posts", "alice", `{"title":"Tagged","content":"Content","tags":[`+strings.Join(tags, ",")+`]}`)
+	expectStatus(t, w, 400)
+	if !strings.Contains(w.Body.String(), core.ErrTooManyTags.Error()) {
+		t.Fatalf("too many tags of a post are not reported as such: %s", w.Body.String())
+	}
+}
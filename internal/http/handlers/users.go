@@ -0,0 +1,134 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/furkanpala/post-app/internal/core"
+	"github.com/furkanpala/post-app/internal/database"
+	httperror "github.com/furkanpala/post-app/internal/http/error"
+	"github.com/gorilla/mux"
+)
+
+// GetProfile handles the GET requests for /users/{username} route.
+// Returns the public profile of the user.
+func (h *Handler) GetProfile(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	user, httpErr := h.findUser(mux.Vars(r)["username"])
+	if httpErr != nil {
+		return httpErr
+	}
+
+	count, err := h.store.CountPosts(database.PostQuery{Author: user.Username})
+	if err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	followers, err := h.store.CountFollowers(user.Username)
+	if err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	following, err := h.store.CountFollowing(user.Username)
+	if err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	profile := core.Profile{
+		Username:       user.Username,
+		JoinedAt:       user.JoinedAt,
+		PostCount:      count,
+		FollowerCount:  followers,
+		FollowingCount: following,
+	}
+
+	if err := json.NewEncoder(w).Encode(profile); err != nil {
+		return &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	return nil
+}
+
+// GetUserPosts handles the GET requests for /users/{username}/posts route.
+// Returns the page of the posts of the user selected by page query parameter, 1 by default,
+// paged the same way as /posts/page/{page}.
+func (h *Handler) GetUserPosts(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+	user, httpErr := h.findUser(mux.Vars(r)["username"])
+	if httpErr != nil {
+		return httpErr
+	}
+
+	tags, httpErr := queryTags(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	page := r.URL.Query().Get("page")
+	if page == "" {
+		page = "1"
+	}
+
+	return h.getPostsOnPage(w, r, page, database.PostQuery{Author: user.Username, Tags: tags})
+}
+
+// findUser returns the user with given username.
+// Responses with Not Found error if there is no such user.
+func (h *Handler) findUser(username string) (*core.User, *httperror.HTTPError) {
+	user, err := h.store.FindUser(username)
+	if err != nil {
+		return nil, &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	if user == nil {
+		return nil, &httperror.HTTPError{
+			Cause: nil,
+			Type:  httperror.TypeUserNotFound,
+			Info: httperror.ErrorMessage{
+				Title:  "User not found",
+				Detail: "",
+			},
+			Code: 404,
+		}
+	}
+
+	return user, nil
+}
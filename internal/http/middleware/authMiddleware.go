@@ -4,40 +4,97 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/furkanpala/post-app/internal/database"
 	"github.com/furkanpala/post-app/internal/env"
 	httperror "github.com/furkanpala/post-app/internal/http/error"
 	httphandlers "github.com/furkanpala/post-app/internal/http/handlers"
 	jwttoken "github.com/furkanpala/post-app/internal/http/token"
-	"github.com/gorilla/context"
 )
 
-func AuthMiddleware(next httphandlers.RouteHandler) httphandlers.RouteHandler {
+// AuthMiddleware function verifies the bearer access token of the request.
+// If the token is valid and its jti is not in the blacklist of store,
+// next is called with the username of the token in the request context.
+// Users are not looked up here, handlers look up the ones they need the roles of.
+func AuthMiddleware(store database.Store, next httphandlers.RouteHandler) httphandlers.RouteHandler {
 	return httphandlers.RouteHandler(func(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
-		authorization := strings.Split(r.Header.Get("Authorization"), " ")
-
-		if len(authorization) != 2 {
-			return &httperror.HTTPError{
-				Cause: nil,
-				Info: httperror.ErrorMessage{
-					Title:  "Unauthorized",
-					Detail: "",
-				},
-				Code: 401,
-			}
+		username, httpErr := authenticate(store, r)
+		if httpErr != nil {
+			return httpErr
 		}
 
-		accessTokenString := authorization[1]
-
-		var claims jwttoken.Claims
+		next.ServeHTTP(w, httphandlers.WithUsername(r, username))
+		return nil
+	})
+}
 
-		_, httpErr := jwttoken.VerifyToken(accessTokenString, env.AccessTokenSecret, &claims)
-		if httpErr != nil {
+// OptionalAuthMiddleware function is AuthMiddleware for the routes which anyone can access.
+// Requests without a valid bearer access token are passed to next
+// without a username in the request context instead of being rejected.
+func OptionalAuthMiddleware(store database.Store, next httphandlers.RouteHandler) httphandlers.RouteHandler {
+	return httphandlers.RouteHandler(func(w http.ResponseWriter, r *http.Request) *httperror.HTTPError {
+		username, httpErr := authenticate(store, r)
+		if httpErr != nil && httpErr.Code != 401 {
 			return httpErr
 		}
-
-		context.Set(r, "username", claims.Username)
+		if httpErr == nil {
+			r = httphandlers.WithUsername(r, username)
+		}
 
 		next.ServeHTTP(w, r)
 		return nil
 	})
 }
+
+// authenticate verifies the bearer access token of the request,
+// checks that it is not logged out and returns its username
+func authenticate(store database.Store, r *http.Request) (string, *httperror.HTTPError) {
+	authorization := strings.Split(r.Header.Get("Authorization"), " ")
+
+	if len(authorization) != 2 {
+		return "", &httperror.HTTPError{
+			Cause: nil,
+			Type:  httperror.TypeMissingToken,
+			Info: httperror.ErrorMessage{
+				Title:  "Unauthorized",
+				Detail: "",
+			},
+			Code: 401,
+		}
+	}
+
+	accessTokenString := authorization[1]
+
+	var claims jwttoken.Claims
+
+	_, httpErr := jwttoken.VerifyToken(accessTokenString, env.AccessTokenSecret, &claims)
+	if httpErr != nil {
+		return "", httpErr
+	}
+
+	isInBlacklist, err := store.FindJTI(claims.Id)
+	if err != nil {
+		return "", &httperror.HTTPError{
+			Cause: err,
+			Type:  httperror.TypeInternal,
+			Info: httperror.ErrorMessage{
+				Title:  "Internal server error",
+				Detail: "",
+			},
+			Code: 500,
+		}
+	}
+
+	if isInBlacklist {
+		return "", &httperror.HTTPError{
+			Cause: nil,
+			Type:  httperror.TypeRevokedToken,
+			Info: httperror.ErrorMessage{
+				Title:  "Unauthorized",
+				Detail: "Invalid credentials",
+			},
+			Code: 401,
+		}
+	}
+
+	return claims.Username, nil
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	httphandlers "github.com/furkanpala/post-app/internal/http/handlers"
+	uuid "github.com/satori/go.uuid"
+)
+
+// RequestID gives every request a new id, which is the instance of its error response
+// and is logged with the cause of the error.
+// An id sent by the client is replaced so that ids are always unique.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := uuid.NewV4().String()
+		r.Header.Set(httphandlers.RequestIDHeader, id)
+		w.Header().Set(httphandlers.RequestIDHeader, id)
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -15,6 +15,7 @@ func DecodeRequestBody(r *http.Request, v interface{}) *httperror.HTTPError {
 	if err := decoder.Decode(v); err != nil {
 		return &httperror.HTTPError{
 			Cause: err,
+			Type:  httperror.TypeInvalidJSON,
 			Info: httperror.ErrorMessage{
 				Title:  "Invalid JSON",
 				Detail: err.Error(),
@@ -0,0 +1,11 @@
+package request
+
+// PostEdit is the request body of editing a post.
+// Fields left out are not changed.
+type PostEdit struct {
+	Title   *string `json:"title"`
+	Content *string `json:"content"`
+	// Status and PublishAt can only be changed while the post is unpublished
+	Status    *string `json:"status"`
+	PublishAt *int64  `json:"publish_at"`
+}
@@ -0,0 +1,10 @@
+package response
+
+import "github.com/furkanpala/post-app/internal/core"
+
+// CommentsResponse holds a page of comment threads of a post.
+// Count is the number of top level comments on the page.
+type CommentsResponse struct {
+	Comments []core.Comment `json:"comments"`
+	Count    int            `json:"count"`
+}
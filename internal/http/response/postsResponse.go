@@ -5,4 +5,7 @@ import "github.com/furkanpala/post-app/internal/core"
 type PostsResponse struct {
 	Posts []core.Post `json:"posts"`
 	Count int         `json:"count"`
+	// NextCursor points at the last post of the response
+	// and is empty if there are no more posts
+	NextCursor string `json:"next_cursor,omitempty"`
 }
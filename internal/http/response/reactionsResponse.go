@@ -0,0 +1,8 @@
+package response
+
+// ReactionsResponse holds the reaction counts of a post
+// and the types of reaction the requesting user gave to it
+type ReactionsResponse struct {
+	Reactions map[string]int `json:"reactions"`
+	Reacted   []string       `json:"reacted"`
+}
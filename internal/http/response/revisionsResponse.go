@@ -0,0 +1,21 @@
+package response
+
+import (
+	"github.com/furkanpala/post-app/internal/core"
+	"github.com/furkanpala/post-app/internal/diff"
+)
+
+type RevisionsResponse struct {
+	Revisions []core.Revision `json:"revisions"`
+	Count     int             `json:"count"`
+}
+
+// RevisionDiffResponse holds the line-based diffs of title and content
+// between two revisions of a post
+type RevisionDiffResponse struct {
+	PostID  int         `json:"post_id"`
+	From    int         `json:"from"`
+	To      int         `json:"to"`
+	Title   []diff.Line `json:"title"`
+	Content []diff.Line `json:"content"`
+}
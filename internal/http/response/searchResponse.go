@@ -0,0 +1,10 @@
+package response
+
+import "github.com/furkanpala/post-app/internal/core"
+
+// SearchResponse has the same shape as PostsResponse,
+// posts additionally carry highlighted title and a snippet of content
+type SearchResponse struct {
+	Posts []core.SearchResult `json:"posts"`
+	Count int                 `json:"count"`
+}
@@ -0,0 +1,9 @@
+package response
+
+import "github.com/furkanpala/post-app/internal/core"
+
+// TagsResponse holds the tags in use with the number of posts tagged with them
+type TagsResponse struct {
+	Tags  []core.Tag `json:"tags"`
+	Count int        `json:"count"`
+}
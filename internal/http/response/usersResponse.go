@@ -0,0 +1,7 @@
+package response
+
+// UsersResponse holds a list of usernames
+type UsersResponse struct {
+	Users []string `json:"users"`
+	Count int      `json:"count"`
+}
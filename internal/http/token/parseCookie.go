@@ -15,6 +15,7 @@ func ParseCookie(r *http.Request, name string) (*http.Cookie, *httperror.HTTPErr
 	if err != nil {
 		return nil, &httperror.HTTPError{
 			Cause: err,
+			Type:  httperror.TypeMissingRefreshToken,
 			Info: httperror.ErrorMessage{
 				Title:  "Forbidden",
 				Detail: err.Error(),
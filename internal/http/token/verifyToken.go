@@ -16,6 +16,7 @@ func VerifyToken(tokenString, secret string, claims jwt.Claims) (*jwt.Token, *ht
 		case *jwt.ValidationError:
 			return nil, &httperror.HTTPError{
 				Cause: err,
+				Type:  httperror.TypeInvalidToken,
 				Info: httperror.ErrorMessage{
 					Title:  "Unauthorized",
 					Detail: "Invalid credentials",
@@ -25,6 +26,7 @@ func VerifyToken(tokenString, secret string, claims jwt.Claims) (*jwt.Token, *ht
 		default:
 			return nil, &httperror.HTTPError{
 				Cause: err,
+				Type:  httperror.TypeInternal,
 				Info: httperror.ErrorMessage{
 					Title:  "Internal server error",
 					Detail: "",
@@ -36,6 +38,7 @@ func VerifyToken(tokenString, secret string, claims jwt.Claims) (*jwt.Token, *ht
 	if !token.Valid {
 		return nil, &httperror.HTTPError{
 			Cause: nil,
+			Type:  httperror.TypeInvalidToken,
 			Info: httperror.ErrorMessage{
 				Title:  "Unauthorized",
 				Detail: "Invalid credentials",
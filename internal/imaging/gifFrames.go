@@ -0,0 +1,83 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// MaxFrames is the maximum number of frames of a GIF image
+const MaxFrames = 1000
+
+var errMalformedGIF = errors.New("malformed GIF")
+
+// checkGIFFrames walks the blocks of a GIF image without decoding them and fails
+// with ErrTooLarge when it has more than MaxFrames frames or when its frames have
+// more than MaxPixels pixels in total, as every frame is decoded into its own image.
+func checkGIFFrames(data []byte) error {
+	// Header and logical screen descriptor
+	if len(data) < 13 {
+		return errMalformedGIF
+	}
+	i := 13
+	if flags := data[10]; flags&0x80 != 0 {
+		i += 3 << (flags&0x07 + 1)
+	}
+
+	frames, pixels := 0, 0
+	for i < len(data) {
+		switch data[i] {
+		case 0x21: // Extension: label and sub-blocks
+			if i+2 > len(data) {
+				return errMalformedGIF
+			}
+			next, err := skipSubBlocks(data, i+2)
+			if err != nil {
+				return err
+			}
+			i = next
+		case 0x2C: // Image descriptor, color table, LZW code size and sub-blocks
+			if i+11 > len(data) {
+				return errMalformedGIF
+			}
+			width := int(binary.LittleEndian.Uint16(data[i+5:]))
+			height := int(binary.LittleEndian.Uint16(data[i+7:]))
+			frames++
+			pixels += width * height
+			if frames > MaxFrames || pixels > MaxPixels {
+				return ErrTooLarge
+			}
+
+			flags := data[i+9]
+			i += 10
+			if flags&0x80 != 0 {
+				i += 3 << (flags&0x07 + 1)
+			}
+			next, err := skipSubBlocks(data, i+1)
+			if err != nil {
+				return err
+			}
+			i = next
+		case 0x3B: // Trailer
+			return nil
+		default:
+			return errMalformedGIF
+		}
+	}
+
+	return errMalformedGIF
+}
+
+// skipSubBlocks returns the index after the sub-blocks starting at i
+func skipSubBlocks(data []byte, i int) (int, error) {
+	for {
+		if i >= len(data) {
+			return 0, errMalformedGIF
+		}
+		size := int(data[i])
+		i++
+		if size == 0 {
+			return i, nil
+		}
+		i += size
+	}
+}
@@ -0,0 +1,102 @@
+// Package imaging validates uploaded images and strips their metadata.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+// MaxPixels is the maximum width times height of an image.
+// It keeps small files which decode into huge images out.
+const MaxPixels = 40000000
+
+var (
+	// ErrUnsupportedType is returned for files which are not JPEG, PNG or GIF images
+	ErrUnsupportedType = errors.New("only JPEG, PNG and GIF images are supported")
+	// ErrTooLarge is returned for images with more than MaxPixels pixels
+	// and for GIF images with more than MaxFrames frames or MaxPixels pixels in their frames
+	ErrTooLarge = errors.New("image dimensions are too large")
+)
+
+// Image is a cleaned image
+type Image struct {
+	Data        []byte
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// Clean sniffs the type of data, which must be a JPEG, PNG or GIF image,
+// and encodes the image again. Encoding leaves out EXIF and every other metadata,
+// so JPEG images are turned upright for their EXIF Orientation first.
+func Clean(data []byte) (*Image, error) {
+	contentType := http.DetectContentType(data)
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+	default:
+		return nil, ErrUnsupportedType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	if contentType == "image/gif" {
+		switch err := checkGIFFrames(data); err {
+		case nil:
+		case ErrTooLarge:
+			return nil, err
+		default:
+			return nil, ErrUnsupportedType
+		}
+	}
+
+	var buf bytes.Buffer
+	switch contentType {
+	case "image/jpeg":
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, ErrUnsupportedType
+		}
+		// Orientation is applied to the pixels as it is stripped with the rest of EXIF
+		img = orient(img, jpegOrientation(data))
+		config.Width, config.Height = img.Bounds().Dx(), img.Bounds().Dy()
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90})
+		if err != nil {
+			return nil, err
+		}
+	case "image/png":
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, ErrUnsupportedType
+		}
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+	case "image/gif":
+		// Every frame is kept so that animations still play
+		img, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, ErrUnsupportedType
+		}
+		if err := gif.EncodeAll(&buf, img); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Image{
+		Data:        buf.Bytes(),
+		ContentType: contentType,
+		Width:       config.Width,
+		Height:      config.Height,
+	}, nil
+}
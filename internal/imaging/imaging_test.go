@@ -0,0 +1,216 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// halves returns a w×h image whose left half is red and right half is blue
+func halves(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if x < w/2 {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// encodeGIF encodes an animation of the given number of 1×1 frames
+func encodeGIF(t *testing.T, frames int) []byte {
+	anim := &gif.GIF{}
+	for i := 0; i < frames; i++ {
+		anim.Image = append(anim.Image, image.NewPaletted(image.Rect(0, 0, 1, 1), color.Palette{color.Black, color.White}))
+		anim.Delay = append(anim.Delay, 1)
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// withOrientation inserts an EXIF segment with the Orientation tag into a JPEG image
+func withOrientation(data []byte, order binary.ByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 26)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], 0x0112)
+	order.PutUint16(tiff[12:], 3)
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], orientation)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(2+len(payload)))
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
+
+func TestCleanTypes(t *testing.T) {
+	var jpegData bytes.Buffer
+	if err := jpeg.Encode(&jpegData, halves(4, 4), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		data        []byte
+		contentType string
+		err         error
+	}{
+		{"png", encodePNG(t, halves(4, 2)), "image/png", nil},
+		{"jpeg", jpegData.Bytes(), "image/jpeg", nil},
+		{"gif", encodeGIF(t, 3), "image/gif", nil},
+		{"text", []byte("<svg onload=alert(1)>"), "", ErrUnsupportedType},
+		{"truncated png", encodePNG(t, halves(4, 2))[:40], "", ErrUnsupportedType},
+	}
+
+	for _, test := range tests {
+		img, err := Clean(test.data)
+		if err != test.err {
+			t.Errorf("%s: Clean returned error %v, want %v", test.name, err, test.err)
+			continue
+		}
+		if err == nil && img.ContentType != test.contentType {
+			t.Errorf("%s: Clean returned type %s, want %s", test.name, img.ContentType, test.contentType)
+		}
+	}
+}
+
+func TestCleanTooManyPixels(t *testing.T) {
+	data := encodePNG(t, halves(2, 2))
+
+	// Declares 10000×10000 pixels in the IHDR chunk, which follows the 8 byte signature
+	ihdr := data[8+8 : 8+8+13]
+	binary.BigEndian.PutUint32(ihdr[0:], 10000)
+	binary.BigEndian.PutUint32(ihdr[4:], 10000)
+	binary.BigEndian.PutUint32(data[8+8+13:], crc32.ChecksumIEEE(data[8+4:8+8+13]))
+
+	if _, err := Clean(data); err != ErrTooLarge {
+		t.Fatalf("Clean of a 10000×10000 image returned %v, want ErrTooLarge", err)
+	}
+}
+
+func TestCleanGIFFrames(t *testing.T) {
+	if _, err := Clean(encodeGIF(t, MaxFrames)); err != nil {
+		t.Fatalf("Clean of %d frames returned %v", MaxFrames, err)
+	}
+	if _, err := Clean(encodeGIF(t, MaxFrames+1)); err != ErrTooLarge {
+		t.Fatalf("Clean of %d frames returned %v, want ErrTooLarge", MaxFrames+1, err)
+	}
+
+	// Two frames declaring 6000×6000 pixels each, more than MaxPixels together
+	// although the logical screen is 1×1
+	data := encodeGIF(t, 2)
+	descriptor := []byte{0x2C, 0, 0, 0, 0, 1, 0, 1, 0}
+	for i := 0; i < 2; i++ {
+		at := bytes.Index(data, descriptor)
+		if at < 0 {
+			t.Fatal("image descriptor is not found")
+		}
+		binary.LittleEndian.PutUint16(data[at+5:], 6000)
+		binary.LittleEndian.PutUint16(data[at+7:], 6000)
+	}
+	if _, err := Clean(data); err != ErrTooLarge {
+		t.Fatalf("Clean of frames with %d pixels returned %v, want ErrTooLarge", 2*6000*6000, err)
+	}
+}
+
+func TestCleanJPEGOrientation(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, halves(16, 8), &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		data := withOrientation(buf.Bytes(), order, 6)
+		if got := jpegOrientation(data); got != 6 {
+			t.Fatalf("jpegOrientation with %v EXIF = %d, want 6", order, got)
+		}
+
+		img, err := Clean(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if img.Width != 8 || img.Height != 16 {
+			t.Fatalf("image rotated 90° is %d×%d, want 8×16", img.Width, img.Height)
+		}
+		if jpegOrientation(img.Data) != 1 {
+			t.Fatal("cleaned image still has an EXIF orientation")
+		}
+
+		decoded, err := jpeg.Decode(bytes.NewReader(img.Data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Rotated clockwise, the red left half is on the top
+		if r, _, b, _ := decoded.At(4, 3).RGBA(); r < b {
+			t.Errorf("top of the rotated image is not red")
+		}
+		if r, _, b, _ := decoded.At(4, 12).RGBA(); b < r {
+			t.Errorf("bottom of the rotated image is not blue")
+		}
+	}
+}
+
+func TestOrient(t *testing.T) {
+	src := halves(2, 1)
+	red, blue := src.At(0, 0), src.At(1, 0)
+
+	tests := []struct {
+		orientation int
+		w, h        int
+		first       color.Color
+	}{
+		{1, 2, 1, red},
+		{2, 2, 1, blue},
+		{3, 2, 1, blue},
+		{6, 1, 2, red},
+		{8, 1, 2, blue},
+	}
+
+	for _, test := range tests {
+		img := orient(src, test.orientation)
+		bounds := img.Bounds()
+		if bounds.Dx() != test.w || bounds.Dy() != test.h {
+			t.Errorf("orientation %d gives %d×%d, want %d×%d", test.orientation, bounds.Dx(), bounds.Dy(), test.w, test.h)
+			continue
+		}
+		if img.At(0, 0) != test.first {
+			t.Errorf("orientation %d puts %v first, want %v", test.orientation, img.At(0, 0), test.first)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Package markdown renders the Markdown content of posts into HTML
+// which is safe to put into a page as it is.
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// renderer converts GitHub flavored Markdown into HTML.
+// Raw HTML inside Markdown is left out of the output.
+var renderer = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// policy is the allowlist of elements and attributes kept in the rendered HTML.
+// Scripts, styles and event handlers are removed, links may only point to
+// http, https and mailto URLs and are marked nofollow.
+var policy = newPolicy()
+
+// checkboxType matches the type attribute of the checkboxes of task list items
+var checkboxType = regexp.MustCompile(`^checkbox$`)
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowURLSchemes("http", "https", "mailto")
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	// Checkboxes of the task list items of GFM
+	p.AllowAttrs("type").Matching(checkboxType).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+
+	return p
+}
+
+// Render converts the Markdown source into sanitized HTML
+func Render(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := renderer.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+
+	return policy.Sanitize(buf.String()), nil
+}
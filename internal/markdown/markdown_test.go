@@ -0,0 +1,68 @@
+package markdown
+
+import "testing"
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"markdown", "**bold** `code`", "<p><strong>bold</strong> <code>code</code></p>\n"},
+		{"script block", "<script>alert(1)</script>", "\n"},
+		{"inline script", "hi <script>alert(1)</script> there", "<p>hi alert(1) there</p>\n"},
+		{"event handler", `<img src=x onerror=alert(1)>`, "\n"},
+		{"svg", "<svg onload=alert(1)>", "\n"},
+		{"raw link with handler", `<a href="https://example.com" onclick="alert(1)">x</a>`, "<p>x</p>\n"},
+		{"raw javascript link", `<a href="javascript:alert(1)">x</a>`, "<p>x</p>\n"},
+		{"iframe", `<iframe src="https://example.com"></iframe>`, "\n"},
+		{"style", `<div style="color:red">x</div>`, "\n"},
+		{"javascript link", "[x](javascript:alert(1))", "<p>x</p>\n"},
+		{"mixed case javascript link", "[x](JaVaScRiPt:alert(1))", "<p>x</p>\n"},
+		{"vbscript link", "[x](vbscript:msgbox)", "<p>x</p>\n"},
+		{"data link", "[x](data:text/html;base64,PHNjcmlwdD4=)", "<p>x</p>\n"},
+		{"data image", "![x](data:image/png;base64,AAAA)", "<p><img alt=\"x\"></p>\n"},
+		{"https link", "[ok](https://example.com)",
+			"<p><a href=\"https://example.com\" rel=\"nofollow noopener\" target=\"_blank\">ok</a></p>\n"},
+		{"mailto link", "[mail](mailto:a@example.com)", "<p><a href=\"mailto:a@example.com\" rel=\"nofollow\">mail</a></p>\n"},
+		{"raw input", `<input type="text" value="x">`, "\n"},
+		{"task list", "- [ ] todo\n- [x] done",
+			"<ul>\n<li><input disabled=\"\" type=\"checkbox\"> todo</li>\n" +
+				"<li><input checked=\"\" disabled=\"\" type=\"checkbox\"> done</li>\n</ul>\n"},
+	}
+
+	for _, test := range tests {
+		got, err := Render(test.source)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: Render(%q) = %q, want %q", test.name, test.source, got, test.want)
+		}
+	}
+}
+
+// TestPolicy checks the allowlist on HTML which the Markdown renderer does not produce itself,
+// as it keeps the output safe if raw HTML is ever let through
+func TestPolicy(t *testing.T) {
+	tests := []struct {
+		html string
+		want string
+	}{
+		{`<input type="checkbox" checked disabled>`, `<input type="checkbox" checked="" disabled="">`},
+		{`<input type="text" value="x">`, ``},
+		{`<input type="checkbox onfocus" autofocus onfocus="alert(1)">`, ``},
+		{`<input type="image" src="https://example.com/x.png">`, ``},
+		{`<p onmouseover="alert(1)">x</p>`, `<p>x</p>`},
+		{`<a href="javascript:alert(1)">x</a>`, `x`},
+		{`<a href="data:text/html,x">x</a>`, `x`},
+		{`<form action="https://example.com"><button>x</button></form>`, `x`},
+	}
+
+	for _, test := range tests {
+		if got := policy.Sanitize(test.html); got != test.want {
+			t.Errorf("Sanitize(%q) = %q, want %q", test.html, got, test.want)
+		}
+	}
+}
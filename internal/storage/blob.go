@@ -0,0 +1,100 @@
+// Package storage keeps the files uploaded by users.
+package storage
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrInvalidKey is returned for keys which could point outside of the store
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// ReadSeekCloser is a stored file opened for reading
+type ReadSeekCloser interface {
+	io.ReadSeeker
+	io.Closer
+}
+
+// BlobStore keeps files under keys chosen by the caller
+type BlobStore interface {
+	// Put stores the content of r under key, replacing the file with the same key
+	Put(key string, r io.Reader) error
+	// Open opens the file stored under key, an error satisfying os.IsNotExist if there is no such file
+	Open(key string) (ReadSeekCloser, error)
+	// Delete removes the file stored under key
+	Delete(key string) error
+}
+
+// LocalBlobStore is a BlobStore which keeps files in a directory of the local disk
+type LocalBlobStore struct {
+	dir string
+}
+
+var _ BlobStore = (*LocalBlobStore)(nil)
+
+// NewLocalBlobStore returns a LocalBlobStore keeping files in dir, which is created if it does not exist
+func NewLocalBlobStore(dir string) (*LocalBlobStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &LocalBlobStore{dir: dir}, nil
+}
+
+// Put writes the content of r into a temporary file and renames it to key,
+// so a file is never read half written
+func (s *LocalBlobStore) Put(key string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(s.dir, ".upload-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Open opens the file stored under key
+func (s *LocalBlobStore) Open(key string) (ReadSeekCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(path)
+}
+
+// Delete removes the file stored under key
+func (s *LocalBlobStore) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+// path returns the path of the file stored under key
+func (s *LocalBlobStore) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, ".") || strings.ContainsAny(key, `/\`) {
+		return "", ErrInvalidKey
+	}
+
+	return filepath.Join(s.dir, key), nil
+}
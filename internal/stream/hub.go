@@ -0,0 +1,68 @@
+// Package stream fans out the changes of posts to the clients listening to them
+package stream
+
+import "sync"
+
+// SubscriberBuffer is the number of events kept for a subscriber which has not received them yet
+const SubscriberBuffer = 16
+
+// Event is a change sent to the subscribers of a Hub
+type Event struct {
+	// ID is sent to the client to resume from, empty if the event cannot be resumed from
+	ID string
+	// Type names the change, e.g. "post_added"
+	Type string
+	// Data is the payload of the event, it is encoded as JSON
+	Data interface{}
+}
+
+// Hub is an in-process publish/subscribe hub.
+// Publishing never blocks: a subscriber which falls behind by more than
+// SubscriberBuffer events is dropped and its channel is closed.
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+}
+
+// NewHub returns a hub without subscribers
+func NewHub() *Hub {
+	return &Hub{subscribers: make(map[chan Event]struct{})}
+}
+
+// Subscribe returns a channel receiving the events published from now on
+// and a function to stop receiving them, which must be called when the subscriber is done.
+func (h *Hub) Subscribe() (<-chan Event, func()) {
+	events := make(chan Event, SubscriberBuffer)
+
+	h.mu.Lock()
+	h.subscribers[events] = struct{}{}
+	h.mu.Unlock()
+
+	return events, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.remove(events)
+	}
+}
+
+// Publish sends the event to every subscriber
+func (h *Hub) Publish(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for events := range h.subscribers {
+		select {
+		case events <- event:
+		default:
+			h.remove(events)
+		}
+	}
+}
+
+// remove closes the channel of the subscriber if it is still subscribed, h.mu must be held
+func (h *Hub) remove(events chan Event) {
+	if _, ok := h.subscribers[events]; ok {
+		delete(h.subscribers, events)
+		close(events)
+	}
+}
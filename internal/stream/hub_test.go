@@ -0,0 +1,47 @@
+package stream
+
+import "testing"
+
+func TestPublish(t *testing.T) {
+	hub := NewHub()
+	first, unsubscribeFirst := hub.Subscribe()
+	second, unsubscribeSecond := hub.Subscribe()
+	defer unsubscribeSecond()
+
+	hub.Publish(Event{ID: "1", Type: "post_added"})
+	for _, events := range []<-chan Event{first, second} {
+		if event := <-events; event.ID != "1" || event.Type != "post_added" {
+			t.Fatalf("subscriber received %+v, want the published event", event)
+		}
+	}
+
+	unsubscribeFirst()
+	if _, ok := <-first; ok {
+		t.Fatal("channel of an unsubscribed subscriber is open")
+	}
+
+	hub.Publish(Event{ID: "2", Type: "post_added"})
+	if event := <-second; event.ID != "2" {
+		t.Fatalf("subscriber received %+v after another one unsubscribed, want event 2", event)
+	}
+}
+
+func TestPublishDropsSlowSubscribers(t *testing.T) {
+	hub := NewHub()
+	slow, unsubscribe := hub.Subscribe()
+
+	for i := 0; i <= SubscriberBuffer; i++ {
+		hub.Publish(Event{Type: "post_edited"})
+	}
+
+	received := 0
+	for range slow {
+		received++
+	}
+	if received != SubscriberBuffer {
+		t.Fatalf("slow subscriber received %d events before it was dropped, want %d", received, SubscriberBuffer)
+	}
+
+	// Unsubscribing after being dropped does nothing
+	unsubscribe()
+}
@@ -0,0 +1,89 @@
+package validation
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/furkanpala/post-app/internal/core"
+)
+
+// Limits of the fields of users
+const (
+	MinUsernameLength = 3
+	MaxUsernameLength = 20
+	MinPasswordLength = 6
+)
+
+// Limits of the fields of posts
+const (
+	MaxTitleLength   = 300
+	MaxContentLength = 50000
+)
+
+// Limits of the fields of comments
+const (
+	MaxCommentLength = 5000
+)
+
+// User checks the username and the password of a registering user
+func User(user *core.User) Errors {
+	return Collect(
+		String("username", user.Username, MinLength("Username", MinUsernameLength), MaxLength("Username", MaxUsernameLength)),
+		String("password", user.Password, MinLength("Password", MinPasswordLength)),
+	)
+}
+
+// Post checks a new post. Status of the post must already be defaulted.
+func Post(post *core.Post) Errors {
+	return Collect(
+		PostTitle(post.Title),
+		PostContent(post.Content),
+		Tags(post.Tags),
+		Check("attachment_ids", len(post.AttachmentIDs) <= core.MaxAttachments, CodeTooMany,
+			"A post can have at most "+strconv.Itoa(core.MaxAttachments)+" attachments"),
+		Status(post.Status, post.PublishAt),
+	)
+}
+
+// PostTitle checks the title of a new or edited post
+func PostTitle(title string) Errors {
+	return String("title", title, Required("Title"), MaxLength("Title", MaxTitleLength))
+}
+
+// PostContent checks the content of a new or edited post
+func PostContent(content string) Errors {
+	return String("content", content, Required("Content"), MaxLength("Content", MaxContentLength))
+}
+
+// Comment checks a new or edited comment
+func Comment(comment *core.Comment) Errors {
+	return String("content", comment.Content, Required("Content"), MaxLength("Content", MaxCommentLength))
+}
+
+// Tags checks that tags can be normalized with core.NormalizeTags
+func Tags(tags []string) Errors {
+	_, err := core.NormalizeTags(tags)
+	switch err {
+	case nil:
+		return nil
+	case core.ErrTooManyTags:
+		return Errors{{Field: "tags", Code: CodeTooMany, Message: err.Error()}}
+	default:
+		return Errors{{Field: "tags", Code: CodeInvalid, Message: err.Error()}}
+	}
+}
+
+// Status checks the status and the publishing time of a post.
+// Only scheduled posts have a publishing time, which must be in the future.
+func Status(status string, publishAt int64) Errors {
+	if errs := String("status", status, OneOf("Status", core.PostDraft, core.PostScheduled, core.PostPublished)); errs != nil {
+		return errs
+	}
+
+	if status == core.PostScheduled {
+		return Check("publish_at", publishAt > time.Now().Unix(), CodeInvalid,
+			"Publishing time of a scheduled post must be in the future")
+	}
+
+	return Check("publish_at", publishAt == 0, CodeInvalid, "Publishing time can only be set for scheduled posts")
+}
@@ -0,0 +1,129 @@
+// Package validation checks the values sent by clients field by field.
+// Every failure is reported as a FieldError with a stable code clients can act on.
+package validation
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Codes of field errors
+const (
+	CodeRequired = "required"
+	CodeTooShort = "too_short"
+	CodeTooLong  = "too_long"
+	CodeTooMany  = "too_many"
+	CodeInvalid  = "invalid"
+)
+
+// FieldError describes why a field is invalid.
+// Field is the JSON name of the field, empty if the error is about the value as a whole.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Errors are the failures of a validation, nil if the value is valid
+type Errors []FieldError
+
+// Error joins the messages of the failures
+func (e Errors) Error() string {
+	return strings.Join(e.Messages(), "; ")
+}
+
+// Messages returns the messages of the failures in order
+func (e Errors) Messages() []string {
+	messages := make([]string, len(e))
+	for i, fieldError := range e {
+		messages[i] = fieldError.Message
+	}
+
+	return messages
+}
+
+// Collect concatenates the failures of several validations
+func Collect(errs ...Errors) Errors {
+	var all Errors
+	for _, e := range errs {
+		all = append(all, e...)
+	}
+
+	return all
+}
+
+// Check reports a failure of field with given code and message unless valid
+func Check(field string, valid bool, code, message string) Errors {
+	if valid {
+		return nil
+	}
+
+	return Errors{{Field: field, Code: code, Message: message}}
+}
+
+// StringRule checks a string, returning the code and the message of the failure
+// or an empty code if the string passes
+type StringRule func(value string) (code, message string)
+
+// String checks value of field against rules in order, stopping at the first failing one
+func String(field, value string, rules ...StringRule) Errors {
+	for _, rule := range rules {
+		if code, message := rule(value); code != "" {
+			return Errors{{Field: field, Code: code, Message: message}}
+		}
+	}
+
+	return nil
+}
+
+// Required fails on empty strings. label names the field in the message, e.g. "Title".
+func Required(label string) StringRule {
+	return func(value string) (string, string) {
+		if value == "" {
+			return CodeRequired, label + " required"
+		}
+		return "", ""
+	}
+}
+
+// MinLength fails on strings of fewer than min characters
+func MinLength(label string, min int) StringRule {
+	return func(value string) (string, string) {
+		if utf8.RuneCountInString(value) < min {
+			return CodeTooShort, "Too short " + strings.ToLower(label) + " - Minimum " + strconv.Itoa(min) + " characters"
+		}
+		return "", ""
+	}
+}
+
+// MaxLength fails on strings of more than max characters
+func MaxLength(label string, max int) StringRule {
+	return func(value string) (string, string) {
+		if utf8.RuneCountInString(value) > max {
+			return CodeTooLong, "Too long " + strings.ToLower(label) + " - Maximum " + strconv.Itoa(max) + " characters"
+		}
+		return "", ""
+	}
+}
+
+// OneOf fails on strings other than the given ones
+func OneOf(label string, values ...string) StringRule {
+	return func(value string) (string, string) {
+		for _, v := range values {
+			if value == v {
+				return "", ""
+			}
+		}
+		return CodeInvalid, label + " must be one of " + joinChoices(values)
+	}
+}
+
+// joinChoices lists values as "a, b and c"
+func joinChoices(values []string) string {
+	if len(values) < 2 {
+		return strings.Join(values, "")
+	}
+
+	return strings.Join(values[:len(values)-1], ", ") + " and " + values[len(values)-1]
+}
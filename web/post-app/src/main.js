@@ -6,6 +6,7 @@ import Dashboard from "./components/Dashboard.vue";
 import Login from "./components/auth/Login.vue";
 import Register from "./components/auth/Register.vue";
 import AddPost from "./components/AddPost.vue";
+import PostPage from "./components/PostPage.vue";
 import { store } from "./store";
 
 Vue.config.productionTip = false;
@@ -22,6 +23,7 @@ const router = new VueRouter({
     { path: "/login", component: Login, meta: { visitorRequired: true } },
     { path: "/register", component: Register, meta: { visitorRequired: true } },
     { path: "/add", component: AddPost, meta: { authRequired: true } },
+    { path: "/post/:id", component: PostPage },
   ],
   mode: "history",
 });
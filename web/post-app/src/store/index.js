@@ -115,7 +115,12 @@ export const store = new Vuex.Store({
       if (context.getters.isLoggedIn) {
         return new Promise((resolve, reject) => {
           axios
-            .post("/token/logout", null, { withCredentials: true })
+            .post("/token/logout", null, {
+              withCredentials: true,
+              headers: {
+                Authorization: "bearer " + this.state.token,
+              },
+            })
             .then((response) => {
               localStorage.removeItem("access_token");
               context.commit("clearToken");